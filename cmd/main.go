@@ -5,6 +5,15 @@ import (
 	"os"
 
 	"github.com/stretchy/stretchy/internal/cmd/apply"
+	"github.com/stretchy/stretchy/internal/cmd/compareclusters"
+	"github.com/stretchy/stretchy/internal/cmd/diff"
+	"github.com/stretchy/stretchy/internal/cmd/history"
+	"github.com/stretchy/stretchy/internal/cmd/importer"
+	"github.com/stretchy/stretchy/internal/cmd/lock"
+	"github.com/stretchy/stretchy/internal/cmd/plan"
+	"github.com/stretchy/stretchy/internal/cmd/prune"
+	"github.com/stretchy/stretchy/internal/cmd/rollback"
+	"github.com/stretchy/stretchy/internal/cmd/status"
 	"github.com/urfave/cli/v2"
 )
 
@@ -17,6 +26,15 @@ func main() {
 		Version: version,
 		Commands: []*cli.Command{
 			apply.GetApplyCommand(),
+			compareclusters.GetCompareClustersCommand(),
+			diff.GetDiffCommand(),
+			history.GetHistoryCommand(),
+			importer.GetImportCommand(),
+			lock.GetLockCommand(),
+			plan.GetPlanCommand(),
+			prune.GetPruneCommand(),
+			rollback.GetRollbackCommand(),
+			status.GetStatusCommand(),
 		},
 	}
 
@@ -13,5 +13,6 @@ require (
 	github.com/r3labs/diff v1.1.0
 	github.com/stretchr/testify v1.8.2
 	github.com/urfave/cli/v2 v2.24.3
+	golang.org/x/term v0.2.0
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -2,15 +2,24 @@ package apply
 
 import (
 	"fmt"
-	"path/filepath"
+	"io"
+	"os"
+	"time"
 
+	"github.com/stretchy/stretchy/internal/cmd/common"
 	"github.com/stretchy/stretchy/internal/cmd/flags"
 	"github.com/stretchy/stretchy/pkg/action"
-	"github.com/stretchy/stretchy/pkg/configuration"
 	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/stretchy/stretchy/pkg/strategy"
 	"github.com/urfave/cli/v2"
 )
 
+// The exit codes of --detect-changes, an error exits with 1.
+const (
+	exitCodeUpdatesPending    = 2
+	exitCodeMigrationsPending = 3
+)
+
 func GetApplyCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "apply",
@@ -18,117 +27,263 @@ func GetApplyCommand() *cli.Command {
 		Flags: flags.Merge(
 			flags.GetConfigurationFlags(),
 			flags.GetElasticSearchFlags(),
-			[]cli.Flag{
-				&cli.StringSliceFlag{
-					Name: "index-names",
-				},
-				&cli.StringFlag{
-					Name:    "index-prefix",
-					EnvVars: []string{"INDEX_PREFIX"},
-				},
-				&cli.BoolFlag{
-					Name:    "enable-soft-update",
-					Usage:   "Enable inplace remapping whenever it's possible",
-					EnvVars: []string{"ENABLE_SOFT_UPDATE"},
-					Value:   true,
-				},
-				&cli.BoolFlag{
-					Name:    "dry-run",
-					EnvVars: []string{"DRY_RUN"},
-					Value:   false,
-				},
-			},
+			flags.GetCompareFlags(),
+			flags.GetLockFlags(),
+			flags.GetHistoryFlags(),
+			flags.GetHistoryRecordFlags(),
+			flags.GetDiffFlags(),
+			flags.GetApplyFlags(),
 		),
 		Action: execute,
 	}
 }
 
 func execute(c *cli.Context) error {
-	indexCollection, err := load(c)
+	applyOptions, err := getApplyOptions(c)
 	if err != nil {
 		return err
 	}
 
-	client, err := elasticsearch.New(
-		elasticsearch.Options{
-			Host:     c.String("elasticsearch-host"),
-			User:     c.String("elasticsearch-user"),
-			Password: c.String("elasticsearch-password"),
-			Debug:    c.Bool("elasticsearch-debug"),
-		},
-	)
+	output, err := getOutputOptions(c)
+	if err != nil {
+		return err
+	}
 
+	client, err := elasticsearch.New(flags.GetElasticSearchOptions(c))
 	if err != nil {
 		return err
 	}
 
-	compareResultCollection, err := compare(
-		c,
-		indexCollection,
-		client,
-	)
+	// A dry run doesn't change anything, it must not block the other jobs.
+	if isDryRun(c) || !c.Bool("lock") {
+		return apply(c, client, applyOptions, output)
+	}
+
+	lock := action.NewLock(client, flags.GetLockOptions(c))
+	if err := lock.Acquire(); err != nil {
+		return err
+	}
+
+	applyOptions.CheckLock = lock.Lost
+
+	err = apply(c, client, applyOptions, output)
+
+	if releaseErr := lock.Release(); err == nil {
+		err = releaseErr
+	}
+
+	return err
+}
+
+func apply(
+	c *cli.Context,
+	client elasticsearch.Client,
+	applyOptions action.ApplyOptions,
+	output outputOptions,
+) error {
+	var compareResultCollection action.CompareResultCollection
+	var err error
+
+	if c.String("plan") != "" {
+		compareResultCollection, err = loadPlan(c.String("plan"), client)
+	} else {
+		compareResultCollection, err = compare(c, client)
+	}
 
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Diffs:\n")
+	if output.reportFormat == nil {
+		common.PrintCompareResults(compareResultCollection)
+	}
 
-	for _, compareResult := range compareResultCollection {
-		fmt.Printf("\tIndex '%s' => %s\n", compareResult.AliasName, compareResult.Result.Action().String())
+	if output.configDiff != nil {
+		if err := common.PrintConfigDiffs(*output.configDiff, compareResultCollection); err != nil {
+			return err
+		}
+	}
 
-		for _, d := range compareResult.Result.Changes() {
-			fmt.Printf("\t\t%s\n", d.String())
+	if isDryRun(c) {
+		if err := writeReport(output.reportFormat, compareResultCollection, nil); err != nil {
+			return err
 		}
+
+		return detectChanges(c, compareResultCollection)
+	}
+
+	outcomes, err := action.NewApply(client, applyOptions).ApplyAll(compareResultCollection)
+
+	if c.Bool("history") {
+		history := action.NewHistory(client, flags.GetHistoryOptions(c))
+
+		// The changes are applied anyway, a missing record must not turn them into a failure.
+		if historyErr := history.RecordAll(outcomes); historyErr != nil {
+			fmt.Fprintf(getLogWriter(c), "Warning: the history could not be recorded: %s\n", historyErr)
+		}
+	}
+
+	// The report describes the failed result too, it is written before the error is returned.
+	if reportErr := writeReport(output.reportFormat, compareResultCollection, outcomes); err == nil {
+		err = reportErr
 	}
 
-	if c.Bool("dry-run") {
+	return err
+}
+
+func isDryRun(c *cli.Context) bool {
+	return c.Bool("dry-run") || c.Bool("detect-changes")
+}
+
+// detectChanges exits with a dedicated code when changes are pending, so that a job can tell them apart.
+func detectChanges(c *cli.Context, compareResultCollection action.CompareResultCollection) error {
+	if !c.Bool("detect-changes") {
 		return nil
 	}
 
-	return apply(client, compareResultCollection)
+	if compareResultCollection.HasAction(strategy.IndexDecisionCreate, strategy.IndexDecisionMigrate) {
+		return cli.Exit("", exitCodeMigrationsPending)
+	}
+
+	if compareResultCollection.HasAction(strategy.IndexDecisionUpdate, strategy.IndexDecisionUpdateAnalysis) {
+		return cli.Exit("", exitCodeUpdatesPending)
+	}
+
+	return nil
+}
+
+// outputOptions is what is printed besides the progress.
+type outputOptions struct {
+	// reportFormat is the format of the report, nil for the text output.
+	reportFormat *action.ReportFormat
+	// configDiff is nil when the configurations aren't printed, they never are with a report.
+	configDiff *action.ConfigDiffOptions
 }
 
-func load(c *cli.Context) (configuration.IndexCollection, error) {
-	configPath, err := filepath.Abs(c.String("path"))
+func getOutputOptions(c *cli.Context) (outputOptions, error) {
+	configDiff, err := flags.GetConfigDiffOptions(c)
 	if err != nil {
-		return nil, err
+		return outputOptions{}, err
 	}
 
-	loadAction := action.NewLoad(configPath)
-	format := c.String("format")
+	if c.String("output") == "text" {
+		return outputOptions{configDiff: configDiff}, nil
+	}
 
-	configurationNames := c.StringSlice("index-names")
-	if len(configurationNames) == 0 {
-		return loadAction.LoadAll(format)
+	reportFormat, err := action.NewReportFormatFromString(c.String("output"))
+	if err != nil {
+		return outputOptions{}, fmt.Errorf("unknown output format '%s'", c.String("output"))
 	}
 
-	indexCollection := configuration.IndexCollection{}
+	return outputOptions{reportFormat: &reportFormat}, nil
+}
 
-	for _, name := range configurationNames {
-		index, err := loadAction.Load(name, c.String("format"))
-		if err != nil {
-			return nil, err
-		}
+func writeReport(
+	reportFormat *action.ReportFormat,
+	compareResultCollection action.CompareResultCollection,
+	outcomes action.ApplyOutcomeCollection,
+) error {
+	if reportFormat == nil {
+		return nil
+	}
+
+	return action.NewReport(compareResultCollection, outcomes).Write(os.Stdout, *reportFormat)
+}
 
-		indexCollection.Load(name, index)
+// getLogWriter returns where the progress is printed, stdout is kept for the report when there is one.
+func getLogWriter(c *cli.Context) io.Writer {
+	if c.String("output") != "text" {
+		return os.Stderr
 	}
 
-	return indexCollection, nil
+	return os.Stdout
 }
 
-func compare(
-	c *cli.Context,
-	indexCollection configuration.IndexCollection,
-	client elasticsearch.Client,
-) (action.CompareResultCollection, error) {
-	compareAction := action.NewCompare(client, c.String("index-prefix"), c.Bool("enable-soft-update"))
+func compare(c *cli.Context, client elasticsearch.Client) (action.CompareResultCollection, error) {
+	indexCollection, err := common.LoadIndexCollection(c)
+	if err != nil {
+		return nil, err
+	}
 
-	return compareAction.CompareAll(indexCollection)
+	return common.Compare(c, indexCollection, client)
 }
 
-func apply(client elasticsearch.Client, compareResultCollection action.CompareResultCollection) error {
-	applyAction := action.NewApply(client)
+func loadPlan(path string, client elasticsearch.Client) (action.CompareResultCollection, error) {
+	plan, err := action.LoadPlan(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := plan.Verify(client); err != nil {
+		return nil, err
+	}
+
+	return plan.Results, nil
+}
 
-	return applyAction.ApplyAll(compareResultCollection)
+func getApplyOptions(c *cli.Context) (action.ApplyOptions, error) {
+	writeSafetyMode, err := action.NewWriteSafetyModeFromString(c.String("write-safety"))
+	if err != nil {
+		return action.ApplyOptions{}, err
+	}
+
+	naming, err := elasticsearch.NewNamingStrategyFromString(c.String("naming-strategy"))
+	if err != nil {
+		return action.ApplyOptions{}, err
+	}
+
+	if writeSafetyMode == action.WriteSafetyCatchUp && c.String("catch-up-timestamp-field") == "" {
+		return action.ApplyOptions{}, fmt.Errorf("catch-up write safety mode requires --catch-up-timestamp-field")
+	}
+
+	return action.ApplyOptions{
+		ReindexPollInterval:  c.Duration("reindex-poll-interval"),
+		ReindexTimeout:       c.Duration("reindex-timeout"),
+		ReindexMaxPollErrors: c.Int("reindex-max-poll-errors"),
+		OnReindexProgress:    newReindexProgressPrinter(getLogWriter(c)),
+		Verify: action.VerifyOptions{
+			CountEnabled: c.Bool("verify-count"),
+			Tolerance:    c.Float64("verify-tolerance"),
+			SampleSize:   c.Int("verify-sample-size"),
+		},
+		WriteSafety: action.WriteSafetyOptions{
+			Mode:           writeSafetyMode,
+			TimestampField: c.String("catch-up-timestamp-field"),
+			CatchUpMargin:  c.Duration("catch-up-margin"),
+		},
+		Naming:  naming,
+		Version: c.App.Version,
+		GitSHA:  c.String("git-sha"),
+	}, nil
+}
+
+func newReindexProgressPrinter(writer io.Writer) func(action.CompareResult, elasticsearch.ReindexTask) {
+	return func(compareResult action.CompareResult, task elasticsearch.ReindexTask) {
+		printReindexProgress(writer, compareResult, task)
+	}
+}
+
+func printReindexProgress(writer io.Writer, compareResult action.CompareResult, task elasticsearch.ReindexTask) {
+	if !task.Completed {
+		fmt.Fprintf(writer, "\tReindex '%s' => %s\n", compareResult.AliasName, task.String())
+		return
+	}
+
+	status := "completed"
+
+	switch {
+	case task.Error != "":
+		status = fmt.Sprintf("failed: %s", task.Error)
+	case task.Validate() != nil:
+		status = fmt.Sprintf("completed with errors (%d failures)", len(task.Failures))
+	}
+
+	fmt.Fprintf(
+		writer,
+		"\tReindex '%s' %s in %s => %s\n",
+		compareResult.AliasName,
+		status,
+		task.RunningTime.Round(time.Millisecond),
+		task.String(),
+	)
 }
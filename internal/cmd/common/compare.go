@@ -0,0 +1,48 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/stretchy/stretchy/pkg/strategy"
+	"github.com/urfave/cli/v2"
+)
+
+func Compare(
+	c *cli.Context,
+	indexCollection configuration.IndexCollection,
+	client elasticsearch.Client,
+) (action.CompareResultCollection, error) {
+	compareAction := action.NewCompare(client, c.String("index-prefix"), c.Bool("enable-soft-update"))
+
+	return compareAction.CompareAll(indexCollection)
+}
+
+func PrintCompareResults(compareResultCollection action.CompareResultCollection) {
+	fmt.Printf("Diffs:\n")
+
+	for _, compareResult := range compareResultCollection {
+		fmt.Printf("\tIndex '%s' => %s\n", compareResult.AliasName, compareResult.Result.Action().String())
+
+		if compareResult.Drift {
+			fmt.Printf(
+				"\t\tDrift: index '%s' has been changed outside of stretchy since its last apply, "+
+					"or a document has added a dynamically mapped field\n",
+				compareResult.CurrentIndexName,
+			)
+		}
+
+		if compareResult.Result.Action() == strategy.IndexDecisionUpdateAnalysis {
+			fmt.Printf(
+				"\t\tWarning: index '%s' will be closed, and unavailable, while its analysis settings are updated\n",
+				compareResult.CurrentIndexName,
+			)
+		}
+
+		for _, d := range compareResult.Result.Changes() {
+			fmt.Printf("\t\t%s\n", d.String())
+		}
+	}
+}
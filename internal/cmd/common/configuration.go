@@ -0,0 +1,42 @@
+package common
+
+import (
+	"path/filepath"
+
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/urfave/cli/v2"
+)
+
+func LoadIndexCollection(c *cli.Context) (configuration.IndexCollection, error) {
+	return LoadIndexCollectionFromPath(c, c.String("path"))
+}
+
+// LoadIndexCollectionFromPath loads the configurations of another directory than the one of the path flag.
+func LoadIndexCollectionFromPath(c *cli.Context, path string) (configuration.IndexCollection, error) {
+	configPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	loadAction := action.NewLoad(configPath)
+	format := c.String("format")
+
+	configurationNames := c.StringSlice("index-names")
+	if len(configurationNames) == 0 {
+		return loadAction.LoadAll(format)
+	}
+
+	indexCollection := configuration.IndexCollection{}
+
+	for _, name := range configurationNames {
+		index, err := loadAction.Load(name, format)
+		if err != nil {
+			return nil, err
+		}
+
+		indexCollection.Load(name, index)
+	}
+
+	return indexCollection, nil
+}
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+func PrintConfigDiffs(options action.ConfigDiffOptions, compareResultCollection action.CompareResultCollection) error {
+	configDiff := action.NewConfigDiff(options)
+
+	for _, compareResult := range compareResultCollection {
+		if compareResult.Result.Action() == strategy.IndexDecisionNone {
+			continue
+		}
+
+		output, err := configDiff.Render(compareResult)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\n%s", output)
+	}
+
+	return nil
+}
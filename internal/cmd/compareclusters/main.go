@@ -0,0 +1,113 @@
+package compareclusters
+
+import (
+	"fmt"
+
+	"github.com/stretchy/stretchy/internal/cmd/common"
+	"github.com/stretchy/stretchy/internal/cmd/flags"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/urfave/cli/v2"
+)
+
+// exitCodeDrift is the exit code when the clusters don't match, an error exits with 1.
+const exitCodeDrift = 2
+
+func GetCompareClustersCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "compare-clusters",
+		Usage: "Compare the indices of the configured aliases, or of the matching aliases, on two clusters",
+		Flags: flags.Merge(
+			flags.GetConfigurationFlags(),
+			flags.GetClusterFlags("source"),
+			flags.GetClusterFlags("target"),
+			[]cli.Flag{
+				flags.GetIndexPrefixFlag(),
+				&cli.StringFlag{
+					Name:  "aliases",
+					Usage: "Compare the aliases matching this pattern, e.g. 'stretchy-*', instead of the configured ones",
+				},
+			},
+		),
+		Action: execute,
+	}
+}
+
+func execute(c *cli.Context) error {
+	source, err := elasticsearch.New(flags.GetClusterOptions(c, "source"))
+	if err != nil {
+		return err
+	}
+
+	target, err := elasticsearch.New(flags.GetClusterOptions(c, "target"))
+	if err != nil {
+		return err
+	}
+
+	compareClusters := action.NewCompareClusters(source, target)
+
+	aliasNames, err := getAliasNames(c, compareClusters)
+	if err != nil {
+		return err
+	}
+
+	clusterCompareResultCollection, err := compareClusters.CompareAll(aliasNames)
+	if err != nil {
+		return err
+	}
+
+	printClusterCompareResults(clusterCompareResultCollection)
+
+	if clusterCompareResultCollection.HasDrift() {
+		return cli.Exit("", exitCodeDrift)
+	}
+
+	return nil
+}
+
+func getAliasNames(c *cli.Context, compareClusters *action.CompareClusters) ([]string, error) {
+	if c.String("aliases") != "" {
+		return compareClusters.ResolveAliases(c.String("aliases"))
+	}
+
+	indexCollection, err := common.LoadIndexCollection(c)
+	if err != nil {
+		return nil, err
+	}
+
+	aliasNames := []string{}
+
+	for _, name := range indexCollection.SortedNames() {
+		aliasNames = append(aliasNames, elasticsearch.ResolveAliasName(c.String("index-prefix"), name))
+	}
+
+	return aliasNames, nil
+}
+
+func printClusterCompareResults(clusterCompareResultCollection action.ClusterCompareResultCollection) {
+	fmt.Printf("Diffs:\n")
+
+	for _, result := range clusterCompareResultCollection {
+		switch {
+		case result.SourceIndexName == "" && result.TargetIndexName == "":
+			fmt.Printf("\tAlias '%s' => Missing on both clusters\n", result.AliasName)
+		case result.SourceIndexName == "":
+			fmt.Printf("\tAlias '%s' => Missing on source\n", result.AliasName)
+		case result.TargetIndexName == "":
+			fmt.Printf("\tAlias '%s' => Missing on target\n", result.AliasName)
+		case result.HasDrift():
+			fmt.Printf(
+				"\tAlias '%s' => Drift between '%s' and '%s'\n",
+				result.AliasName,
+				result.SourceIndexName,
+				result.TargetIndexName,
+			)
+		default:
+			fmt.Printf("\tAlias '%s' => In sync\n", result.AliasName)
+		}
+
+		for _, d := range result.Changes {
+			fmt.Printf("\t\t%s\n", d.String())
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package diff
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// extractGitRef writes the configuration directory of a git ref in a temporary directory,
+// and returns where the configuration directory is in it. The temporary directory is removed by cleanUp.
+// The repository is the one of the configuration directory, wherever stretchy runs from.
+// When the configuration directory doesn't exist at that ref, the returned directory is empty.
+func extractGitRef(ref string, configPath string) (path string, cleanUp func(), err error) {
+	if info, err := os.Stat(configPath); err != nil || !info.IsDir() {
+		return "", nil, fmt.Errorf("configuration directory '%s' doesn't exist", configPath)
+	}
+
+	output, err := git(configPath, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, err
+	}
+
+	topLevel := strings.TrimSpace(output)
+
+	relativePath, err := repositoryPath(topLevel, configPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if _, err := git(topLevel, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return "", nil, fmt.Errorf("'%s' is neither a directory nor a git ref", ref)
+	}
+
+	directory, err := ioutil.TempDir("", "stretchy-diff-")
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanUp = func() {
+		_ = os.RemoveAll(directory)
+	}
+
+	path = filepath.Join(directory, filepath.FromSlash(relativePath))
+
+	if err := extractConfigPath(ref, topLevel, relativePath, directory); err != nil {
+		cleanUp()
+
+		return "", nil, err
+	}
+
+	return path, cleanUp, nil
+}
+
+// extractConfigPath writes the configuration directory of a git ref in the directory. A configuration directory
+// created after, or removed before, that ref is left empty: every configuration is then reported as added or removed.
+func extractConfigPath(ref string, topLevel string, relativePath string, directory string) error {
+	// The path is given relatively to the root of the repository, "." included.
+	if _, err := git(topLevel, "cat-file", "-e", ref+":./"+relativePath); err != nil {
+		return os.MkdirAll(filepath.Join(directory, filepath.FromSlash(relativePath)), 0700)
+	}
+
+	archive, err := git(topLevel, "archive", "--format=tar", ref, "--", relativePath)
+	if err != nil {
+		return fmt.Errorf("cannot read '%s' at git ref '%s': %s", relativePath, ref, err)
+	}
+
+	return extractTar(archive, directory)
+}
+
+// repositoryPath returns the path of the configuration directory relative to the root of the repository.
+func repositoryPath(topLevel string, configPath string) (string, error) {
+	absolutePath, err := filepath.Abs(configPath)
+	if err != nil {
+		return "", err
+	}
+
+	// The root of the repository is given without symbolic links.
+	if resolvedPath, err := filepath.EvalSymlinks(absolutePath); err == nil {
+		absolutePath = resolvedPath
+	}
+
+	relativePath, err := filepath.Rel(topLevel, absolutePath)
+	if err != nil || strings.HasPrefix(relativePath, "..") {
+		return "", fmt.Errorf("configuration directory '%s' is outside of the git repository", configPath)
+	}
+
+	return filepath.ToSlash(relativePath), nil
+}
+
+func extractTar(archive string, directory string) error {
+	reader := tar.NewReader(strings.NewReader(archive))
+
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		path := filepath.Join(directory, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, directory+string(os.PathSeparator)) {
+			return fmt.Errorf("unexpected path '%s' in the git archive", header.Name)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+
+		content, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(path, content, 0600); err != nil {
+			return err
+		}
+	}
+}
+
+func git(directory string, args ...string) (string, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	command := exec.Command("git", args...)
+	command.Dir = directory
+	command.Stdout = stdout
+	command.Stderr = stderr
+
+	if err := command.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", errors.New(message)
+		}
+
+		return "", err
+	}
+
+	return stdout.String(), nil
+}
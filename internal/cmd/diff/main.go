@@ -0,0 +1,132 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/stretchy/stretchy/internal/cmd/flags"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/urfave/cli/v2"
+)
+
+func GetDiffCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "diff",
+		Usage: "Compare two versions of the configurations, e.g. two git refs, without Elasticsearch",
+		Flags: flags.Merge(
+			flags.GetConfigurationFlags(),
+			flags.GetCompareFlags(),
+			[]cli.Flag{
+				&cli.StringFlag{
+					Name:     "from",
+					Usage:    "Directory of the configurations, or git ref whose --path directory is compared",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:  "to",
+					Usage: "Directory of the configurations, or git ref whose --path directory is compared, --path by default",
+				},
+			},
+		),
+		Action: execute,
+	}
+}
+
+func execute(c *cli.Context) error {
+	from, err := load(c, c.String("from"))
+	if err != nil {
+		return err
+	}
+
+	to := c.String("to")
+	if to == "" {
+		to = c.String("path")
+	}
+
+	toCollection, err := load(c, to)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range c.StringSlice("index-names") {
+		if !from.Exist(name) && !toCollection.Exist(name) {
+			return fmt.Errorf("configuration '%s' exists in neither '%s' nor '%s'", name, c.String("from"), to)
+		}
+	}
+
+	diffResultCollection, err := action.NewDiff(c.Bool("enable-soft-update")).DiffAll(from, toCollection)
+	if err != nil {
+		return err
+	}
+
+	printDiffResults(c.String("index-prefix"), diffResultCollection)
+
+	return nil
+}
+
+// load reads the configurations of a directory, or of the --path directory at a git ref.
+func load(c *cli.Context, source string) (configuration.IndexCollection, error) {
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return loadPath(c, source)
+	}
+
+	path, cleanUp, err := extractGitRef(source, c.String("path"))
+	if err != nil {
+		return nil, err
+	}
+
+	defer cleanUp()
+
+	return loadPath(c, path)
+}
+
+// loadPath reads every configuration of the directory, then keeps the --index-names ones:
+// a configuration added or removed between both versions is reported instead of failing the load.
+func loadPath(c *cli.Context, path string) (configuration.IndexCollection, error) {
+	configPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	indexCollection, err := action.NewLoad(configPath).LoadAll(c.String("format"))
+	if err != nil {
+		return nil, err
+	}
+
+	configurationNames := c.StringSlice("index-names")
+	if len(configurationNames) == 0 {
+		return indexCollection, nil
+	}
+
+	namedIndexCollection := configuration.IndexCollection{}
+
+	for _, name := range configurationNames {
+		if index, exist := indexCollection[name]; exist {
+			namedIndexCollection.Load(name, index)
+		}
+	}
+
+	return namedIndexCollection, nil
+}
+
+func printDiffResults(indexPrefix string, diffResultCollection action.DiffResultCollection) {
+	fmt.Printf("Diffs:\n")
+
+	for _, diffResult := range diffResultCollection {
+		aliasName := elasticsearch.ResolveAliasName(indexPrefix, diffResult.ConfigurationName)
+
+		if diffResult.Removed {
+			fmt.Printf("\tIndex '%s' => Removed, apply leaves the index untouched\n", aliasName)
+			continue
+		}
+
+		fmt.Printf("\tIndex '%s' => %s\n", aliasName, diffResult.Result.Action().String())
+
+		for _, d := range diffResult.Result.Changes() {
+			fmt.Printf("\t\t%s\n", d.String())
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package flags
+
+import (
+	"time"
+
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/urfave/cli/v2"
+)
+
+// GetApplyFlags are the flags of the apply command besides the ones shared with the other commands.
+func GetApplyFlags() []cli.Flag {
+	return Merge(
+		[]cli.Flag{
+			GetTakeLockFlag(),
+			&cli.BoolFlag{
+				Name:    "dry-run",
+				EnvVars: []string{"DRY_RUN"},
+				Value:   false,
+			},
+			&cli.BoolFlag{
+				Name:    "detect-changes",
+				Usage:   "Only compare, and exit with 2 when updates are pending, 3 when indices are created or migrated",
+				EnvVars: []string{"DETECT_CHANGES"},
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"report"},
+				Usage:   "Output format: text, or json, yaml and markdown to print a report on stdout",
+				EnvVars: []string{"OUTPUT"},
+				Value:   "text",
+			},
+			&cli.StringFlag{
+				Name:  "plan",
+				Usage: "Execute a plan file created by the 'plan' command instead of the configuration files",
+			},
+			&cli.StringFlag{
+				Name:    "naming-strategy",
+				Usage:   "How the created indices are named: timestamp, timestamp-ms, date, version or hash",
+				EnvVars: []string{"NAMING_STRATEGY"},
+				Value:   elasticsearch.NamingStrategyTimestamp.String(),
+			},
+			&cli.StringFlag{
+				Name:    "git-sha",
+				Usage:   "Git commit of the configuration files, recorded in the metadata of the applied indices",
+				EnvVars: []string{"STRETCHY_GIT_SHA"},
+			},
+		},
+		getReindexFlags(),
+		getVerifyFlags(),
+		getWriteSafetyFlags(),
+	)
+}
+
+// getReindexFlags configure how the reindex task of a migration is followed.
+func getReindexFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.DurationFlag{
+			Name:    "reindex-poll-interval",
+			Usage:   "How often the progress of a reindex task is checked",
+			EnvVars: []string{"REINDEX_POLL_INTERVAL"},
+			Value:   5 * time.Second,
+		},
+		&cli.DurationFlag{
+			Name:    "reindex-timeout",
+			Usage:   "How long a reindex task may run before it's cancelled, 0 waits until it's completed",
+			EnvVars: []string{"REINDEX_TIMEOUT"},
+			Value:   0,
+		},
+		&cli.IntFlag{
+			Name:    "reindex-max-poll-errors",
+			Usage:   "Number of consecutive failed checks of a reindex task tolerated before it's cancelled",
+			EnvVars: []string{"REINDEX_MAX_POLL_ERRORS"},
+			Value:   5,
+		},
+	}
+}
+
+// getVerifyFlags configure the checks made on the new index before the alias is moved.
+func getVerifyFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "verify-count",
+			Usage:   "Compare the documents count of the source and target indices before moving the alias",
+			EnvVars: []string{"VERIFY_COUNT"},
+			Value:   true,
+		},
+		&cli.IntFlag{
+			Name:    "verify-sample-size",
+			Usage:   "Number of sampled documents whose content is compared before moving the alias",
+			EnvVars: []string{"VERIFY_SAMPLE_SIZE"},
+			Value:   0,
+		},
+		&cli.Float64Flag{
+			Name:    "verify-tolerance",
+			Usage:   "Maximum divergence, in percent, accepted between the source and target indices",
+			EnvVars: []string{"VERIFY_TOLERANCE"},
+			Value:   0,
+		},
+	}
+}
+
+// getWriteSafetyFlags configure how the writes made during a migration are preserved.
+func getWriteSafetyFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "write-safety",
+			Usage:   "How writes made during a migration are preserved: none, block-writes, or catch-up without deletions",
+			EnvVars: []string{"WRITE_SAFETY"},
+			Value:   action.WriteSafetyNone.String(),
+		},
+		&cli.StringFlag{
+			Name:    "catch-up-timestamp-field",
+			Usage:   "Date field updated on each write, used by the catch-up write safety mode",
+			EnvVars: []string{"CATCH_UP_TIMESTAMP_FIELD"},
+		},
+		&cli.DurationFlag{
+			Name:    "catch-up-margin",
+			Usage:   "Safety margin applied to the migration start by the catch-up write safety mode",
+			EnvVars: []string{"CATCH_UP_MARGIN"},
+			Value:   time.Minute,
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package flags
+
+import "github.com/urfave/cli/v2"
+
+func GetCompareFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name: "index-names",
+		},
+		GetIndexPrefixFlag(),
+		&cli.BoolFlag{
+			Name:    "enable-soft-update",
+			Usage:   "Enable inplace remapping whenever it's possible",
+			EnvVars: []string{"ENABLE_SOFT_UPDATE"},
+			Value:   true,
+		},
+	}
+}
+
+func GetIndexPrefixFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "index-prefix",
+		EnvVars: []string{"INDEX_PREFIX"},
+	}
+}
@@ -0,0 +1,115 @@
+package flags
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+// sideBySideMinWidth is the terminal width from which the auto diff layout is side-by-side.
+const sideBySideMinWidth = 160
+
+func GetDiffFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "diff",
+			Usage:   "Print the current and new configurations of the changed indices: none, unified, side-by-side or auto",
+			EnvVars: []string{"DIFF"},
+			Value:   "none",
+		},
+		&cli.StringFlag{
+			Name:    "diff-syntax",
+			Usage:   "Syntax of the printed configurations: yaml or json",
+			EnvVars: []string{"DIFF_SYNTAX"},
+			Value:   action.ConfigDiffYAML.String(),
+		},
+		&cli.IntFlag{
+			Name:    "diff-context",
+			Usage:   "Number of unchanged lines printed around the changes",
+			EnvVars: []string{"DIFF_CONTEXT"},
+			Value:   3,
+		},
+		&cli.StringFlag{
+			Name:    "color",
+			Usage:   "Color the diff: auto, always or never. auto colors a terminal unless NO_COLOR is set",
+			EnvVars: []string{"COLOR"},
+			Value:   "auto",
+		},
+	}
+}
+
+// GetConfigDiffOptions returns nil when the diff isn't printed.
+func GetConfigDiffOptions(c *cli.Context) (*action.ConfigDiffOptions, error) {
+	if c.String("diff") == "none" {
+		return nil, nil
+	}
+
+	syntax, err := action.NewConfigDiffSyntaxFromString(c.String("diff-syntax"))
+	if err != nil {
+		return nil, err
+	}
+
+	width := terminalWidth()
+	layout := action.ConfigDiffUnified
+
+	if c.String("diff") == "auto" {
+		if isTerminal(os.Stdout) && width >= sideBySideMinWidth {
+			layout = action.ConfigDiffSideBySide
+		}
+	} else {
+		layout, err = action.NewConfigDiffLayoutFromString(c.String("diff"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	color, err := useColor(c.String("color"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &action.ConfigDiffOptions{
+		Layout:  layout,
+		Syntax:  syntax,
+		Context: c.Int("diff-context"),
+		Width:   width,
+		Color:   color,
+	}, nil
+}
+
+// useColor follows https://no-color.org: a non empty NO_COLOR disables the automatic colors.
+func useColor(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		return os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout), nil
+	}
+
+	return false, fmt.Errorf("unknown color mode '%s'", mode)
+}
+
+func isTerminal(file *os.File) bool {
+	return term.IsTerminal(int(file.Fd()))
+}
+
+// terminalWidth is the width of the terminal printing stdout. COLUMNS, which shells set without exporting it,
+// is only a fallback for when stdout isn't a terminal, e.g. piped through less. It is 0 when unknown.
+func terminalWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		return width
+	}
+
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
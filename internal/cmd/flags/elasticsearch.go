@@ -1,28 +1,149 @@
 package flags
 
-import "github.com/urfave/cli/v2"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/urfave/cli/v2"
+)
 
 func GetElasticSearchFlags() []cli.Flag {
+	return getElasticSearchFlags("elasticsearch", "ELASTICSEARCH_")
+}
+
+func GetElasticSearchOptions(c *cli.Context) elasticsearch.Options {
+	return getElasticSearchOptions(c, "elasticsearch")
+}
+
+// GetClusterFlags returns the connection flags of one of several clusters: --<cluster>-host, --<cluster>-user...
+// They are read from the <CLUSTER>_ELASTICSEARCH_HOST, <CLUSTER>_ELASTICSEARCH_USER... environment variables.
+func GetClusterFlags(cluster string) []cli.Flag {
+	return getElasticSearchFlags(cluster, strings.ToUpper(cluster)+"_ELASTICSEARCH_")
+}
+
+func GetClusterOptions(c *cli.Context, cluster string) elasticsearch.Options {
+	return getElasticSearchOptions(c, cluster)
+}
+
+func getElasticSearchFlags(prefix string, envPrefix string) []cli.Flag {
 	return []cli.Flag{
 		&cli.StringFlag{
-			Name:     "elasticsearch-host",
-			EnvVars:  []string{"ELASTICSEARCH_HOST"},
+			Name:     prefix + "-host",
+			EnvVars:  []string{envPrefix + "HOST"},
 			Required: true,
 		},
 		&cli.StringFlag{
-			Name:     "elasticsearch-user",
-			EnvVars:  []string{"ELASTICSEARCH_USER"},
+			Name:     prefix + "-user",
+			EnvVars:  []string{envPrefix + "USER"},
 			Required: false,
 		},
 		&cli.StringFlag{
-			Name:     "elasticsearch-password",
-			EnvVars:  []string{"ELASTICSEARCH_PASSWORD"},
+			Name:     prefix + "-password",
+			EnvVars:  []string{envPrefix + "PASSWORD"},
 			Required: false,
 		},
 		&cli.BoolFlag{
-			Name:     "elasticsearch-debug",
-			EnvVars:  []string{"ELASTICSEARCH_DEBUG"},
+			Name:     prefix + "-debug",
+			EnvVars:  []string{envPrefix + "DEBUG"},
 			Required: false,
 		},
+		&cli.StringFlag{
+			Name:    prefix + "-ca-file",
+			Usage:   "PEM bundle of the certificate authorities trusted besides the system ones",
+			EnvVars: []string{envPrefix + "CA_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    prefix + "-cert-file",
+			Usage:   "PEM client certificate presented to the cluster, for mutual TLS",
+			EnvVars: []string{envPrefix + "CERT_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    prefix + "-key-file",
+			Usage:   "PEM key of the client certificate",
+			EnvVars: []string{envPrefix + "KEY_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    prefix + "-server-name",
+			Usage:   "Name verified in the certificate of the cluster, the host name by default",
+			EnvVars: []string{envPrefix + "SERVER_NAME"},
+		},
+		&cli.BoolFlag{
+			Name:    prefix + "-insecure-skip-verify",
+			Usage:   "Accept any certificate of the cluster, for testing only",
+			EnvVars: []string{envPrefix + "INSECURE_SKIP_VERIFY"},
+		},
+		&cli.StringFlag{
+			Name:    prefix + "-api-key",
+			Usage:   "Base64 encoded id:api_key pair of an API key, sent as \"Authorization: ApiKey\"",
+			EnvVars: []string{envPrefix + "API_KEY"},
+		},
+		&cli.StringFlag{
+			Name:    prefix + "-api-key-file",
+			Usage:   "File containing the API key",
+			EnvVars: []string{envPrefix + "API_KEY_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    prefix + "-bearer-token",
+			Usage:   "Token sent as \"Authorization: Bearer\", e.g. a service account token",
+			EnvVars: []string{envPrefix + "BEARER_TOKEN"},
+		},
+		&cli.StringFlag{
+			Name:    prefix + "-bearer-token-file",
+			Usage:   "File containing the bearer token",
+			EnvVars: []string{envPrefix + "BEARER_TOKEN_FILE"},
+		},
+		&cli.StringSliceFlag{
+			Name:    prefix + "-header",
+			Usage:   "Header added to every request, as \"Name: value\"",
+			EnvVars: []string{envPrefix + "HEADERS"},
+			Action: func(c *cli.Context, headers []string) error {
+				_, err := parseHeaders(headers)
+
+				return err
+			},
+		},
 	}
 }
+
+func getElasticSearchOptions(c *cli.Context, prefix string) elasticsearch.Options {
+	// The headers are already validated by the action of the flag.
+	headers, _ := parseHeaders(c.StringSlice(prefix + "-header"))
+
+	return elasticsearch.Options{
+		Host:     c.String(prefix + "-host"),
+		User:     c.String(prefix + "-user"),
+		Password: c.String(prefix + "-password"),
+		Debug:    c.Bool(prefix + "-debug"),
+
+		CAFile:             c.String(prefix + "-ca-file"),
+		CertFile:           c.String(prefix + "-cert-file"),
+		KeyFile:            c.String(prefix + "-key-file"),
+		ServerName:         c.String(prefix + "-server-name"),
+		InsecureSkipVerify: c.Bool(prefix + "-insecure-skip-verify"),
+
+		APIKey:          c.String(prefix + "-api-key"),
+		APIKeyFile:      c.String(prefix + "-api-key-file"),
+		BearerToken:     c.String(prefix + "-bearer-token"),
+		BearerTokenFile: c.String(prefix + "-bearer-token-file"),
+		Headers:         headers,
+	}
+}
+
+// parseHeaders parses "Name: value" headers.
+func parseHeaders(headers []string) (map[string]string, error) {
+	parsedHeaders := map[string]string{}
+
+	for _, header := range headers {
+		parts := strings.SplitN(header, ":", 2)
+
+		name := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || name == "" {
+			return nil, fmt.Errorf("invalid header '%s', expected 'Name: value'", header)
+		}
+
+		parsedHeaders[name] = strings.TrimSpace(parts[1])
+	}
+
+	return parsedHeaders, nil
+}
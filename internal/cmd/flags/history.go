@@ -0,0 +1,41 @@
+package flags
+
+import (
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/urfave/cli/v2"
+)
+
+func GetHistoryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "history-index",
+			Usage:   "Index storing the history of the applied changes",
+			EnvVars: []string{"HISTORY_INDEX"},
+			Value:   action.DefaultHistoryIndexName,
+		},
+	}
+}
+
+// GetHistoryRecordFlags are the flags of the commands recording their changes in the history.
+func GetHistoryRecordFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "history",
+			Usage:   "Record the applied changes in the history index, which needs the right to write it",
+			EnvVars: []string{"STRETCHY_HISTORY"},
+			Value:   false,
+		},
+		&cli.StringFlag{
+			Name:    "history-user",
+			Usage:   "User recorded in the history, the current user by default",
+			EnvVars: []string{"STRETCHY_USER"},
+		},
+	}
+}
+
+func GetHistoryOptions(c *cli.Context) action.HistoryOptions {
+	return action.HistoryOptions{
+		IndexName: c.String("history-index"),
+		User:      c.String("history-user"),
+	}
+}
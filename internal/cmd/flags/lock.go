@@ -0,0 +1,46 @@
+package flags
+
+import (
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/urfave/cli/v2"
+)
+
+func GetLockFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "lock-index",
+			Usage:   "Index storing the lock taken by apply",
+			EnvVars: []string{"LOCK_INDEX"},
+			Value:   action.DefaultLockIndexName,
+		},
+		&cli.StringFlag{
+			Name:    "lock-owner",
+			Usage:   "Name of the job holding the lock, the host name and the process id by default",
+			EnvVars: []string{"STRETCHY_LOCK_OWNER"},
+		},
+		&cli.DurationFlag{
+			Name:    "lock-ttl",
+			Usage:   "How long the lock of a job that stopped extending it is held",
+			EnvVars: []string{"LOCK_TTL"},
+			Value:   action.DefaultLockTTL,
+		},
+	}
+}
+
+// GetTakeLockFlag enables the lock for the commands changing the cluster.
+func GetTakeLockFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:    "lock",
+		Usage:   "Take the cluster lock, so that concurrent jobs cannot change the same indices",
+		EnvVars: []string{"LOCK"},
+		Value:   true,
+	}
+}
+
+func GetLockOptions(c *cli.Context) action.LockOptions {
+	return action.LockOptions{
+		IndexName: c.String("lock-index"),
+		Owner:     c.String("lock-owner"),
+		TTL:       c.Duration("lock-ttl"),
+	}
+}
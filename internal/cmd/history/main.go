@@ -0,0 +1,152 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/stretchy/stretchy/internal/cmd/flags"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/stretchy/stretchy/pkg/strategy"
+	"github.com/urfave/cli/v2"
+)
+
+func GetHistoryCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "history",
+		Usage:     "List the changes applied on the cluster",
+		ArgsUsage: "[alias]",
+		Flags: flags.Merge(
+			flags.GetElasticSearchFlags(),
+			flags.GetHistoryFlags(),
+			[]cli.Flag{
+				flags.GetIndexPrefixFlag(),
+				&cli.StringFlag{
+					Name:  "action",
+					Usage: "Only list an action: Create, Migrate, Update, UpdateAnalysis or Rollback",
+				},
+				&cli.StringFlag{
+					Name:  "outcome",
+					Usage: "Only list an outcome: success or failure",
+				},
+				&cli.DurationFlag{
+					Name:  "since",
+					Usage: "Only list the changes applied during this period, e.g. 24h",
+				},
+				&cli.IntFlag{
+					Name:  "limit",
+					Usage: "Maximum number of listed changes",
+					Value: action.DefaultHistoryLimit,
+				},
+				&cli.StringFlag{
+					Name:  "output",
+					Usage: "Output format: table or json",
+					Value: "table",
+				},
+			},
+		),
+		Action: execute,
+	}
+}
+
+func execute(c *cli.Context) error {
+	if c.NArg() > 1 {
+		return fmt.Errorf("expected at most one alias, got %d arguments", c.NArg())
+	}
+
+	filter, err := getHistoryFilter(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := elasticsearch.New(flags.GetElasticSearchOptions(c))
+	if err != nil {
+		return err
+	}
+
+	records, err := action.NewHistory(client, flags.GetHistoryOptions(c)).List(filter)
+	if err != nil {
+		return err
+	}
+
+	switch c.String("output") {
+	case "table":
+		return printTable(records)
+	case "json":
+		return printJSON(records)
+	}
+
+	return fmt.Errorf("unknown output format '%s'", c.String("output"))
+}
+
+func getHistoryFilter(c *cli.Context) (action.HistoryFilter, error) {
+	filter := action.HistoryFilter{
+		Limit: c.Int("limit"),
+	}
+
+	if c.NArg() == 1 {
+		filter.AliasName = elasticsearch.ResolveAliasName(c.String("index-prefix"), c.Args().First())
+	}
+
+	if c.String("action") != "" {
+		indexAction, err := strategy.NewIndexActionFromString(c.String("action"))
+		if err != nil {
+			return filter, err
+		}
+
+		filter.Action = &indexAction
+	}
+
+	if c.String("outcome") != "" {
+		outcome, err := action.NewHistoryOutcomeFromString(c.String("outcome"))
+		if err != nil {
+			return filter, err
+		}
+
+		filter.Outcome = &outcome
+	}
+
+	if c.Duration("since") > 0 {
+		filter.Since = time.Now().Add(-c.Duration("since"))
+	}
+
+	return filter, nil
+}
+
+func printTable(records []action.HistoryRecord) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "STARTED AT\tALIAS\tACTION\tOUTCOME\tPREVIOUS INDEX\tINDEX\tDURATION\tUSER\tHOST")
+
+	for _, record := range records {
+		fmt.Fprintf(
+			writer,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			record.StartedAt.Format(time.RFC3339),
+			record.AliasName,
+			record.Action.String(),
+			record.Outcome.String(),
+			record.PreviousIndexName,
+			record.IndexName,
+			(time.Duration(record.DurationMillis) * time.Millisecond).String(),
+			record.User,
+			record.Host,
+		)
+	}
+
+	return writer.Flush()
+}
+
+func printJSON(records []action.HistoryRecord) error {
+	content, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(content))
+
+	return nil
+}
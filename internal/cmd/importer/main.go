@@ -0,0 +1,77 @@
+package importer
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/stretchy/stretchy/internal/cmd/flags"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/urfave/cli/v2"
+)
+
+func GetImportCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Write configuration files from the live indices",
+		ArgsUsage: "<alias|index|pattern>...",
+		Flags: flags.Merge(
+			flags.GetConfigurationFlags(),
+			flags.GetElasticSearchFlags(),
+			[]cli.Flag{
+				flags.GetIndexPrefixFlag(),
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Only show the indices that would be imported",
+				},
+			},
+		),
+		Action: execute,
+	}
+}
+
+func execute(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("expected at least one alias, index or pattern")
+	}
+
+	configPath, err := filepath.Abs(c.String("path"))
+	if err != nil {
+		return err
+	}
+
+	client, err := elasticsearch.New(flags.GetElasticSearchOptions(c))
+	if err != nil {
+		return err
+	}
+
+	importAction := action.NewImport(client, c.String("index-prefix"))
+
+	importResultCollection, err := importAction.Resolve(c.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Import:\n")
+
+	for _, importResult := range importResultCollection {
+		fmt.Printf(
+			"\tAlias '%s' (index '%s') => %s\n",
+			importResult.AliasName,
+			importResult.IndexName,
+			importResult.ConfigurationName,
+		)
+	}
+
+	if c.Bool("dry-run") {
+		return nil
+	}
+
+	if err := importAction.Save(importResultCollection, configPath, c.String("format")); err != nil {
+		return err
+	}
+
+	fmt.Printf("Configurations saved to '%s'\n", configPath)
+
+	return nil
+}
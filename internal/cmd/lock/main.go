@@ -0,0 +1,97 @@
+package lock
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stretchy/stretchy/internal/cmd/flags"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/urfave/cli/v2"
+)
+
+func GetLockCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "lock",
+		Usage: "Inspect the lock taken by apply",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "status",
+				Usage:  "Show which job holds the lock",
+				Flags:  flags.Merge(flags.GetElasticSearchFlags(), flags.GetLockFlags()),
+				Action: executeStatus,
+			},
+			{
+				Name:   "force-release",
+				Usage:  "Release the lock of a stuck job",
+				Flags:  flags.Merge(flags.GetElasticSearchFlags(), flags.GetLockFlags()),
+				Action: executeForceRelease,
+			},
+		},
+	}
+}
+
+func executeStatus(c *cli.Context) error {
+	lock, err := newLock(c)
+	if err != nil {
+		return err
+	}
+
+	info, err := lock.Status()
+	if err != nil {
+		return err
+	}
+
+	if info == nil {
+		fmt.Printf("Lock: released\n")
+		return nil
+	}
+
+	printLockInfo(*info)
+
+	return nil
+}
+
+func executeForceRelease(c *cli.Context) error {
+	lock, err := newLock(c)
+	if err != nil {
+		return err
+	}
+
+	info, err := lock.ForceRelease()
+	if err != nil {
+		return err
+	}
+
+	if info == nil {
+		fmt.Printf("Lock: already released\n")
+		return nil
+	}
+
+	printLockInfo(*info)
+	fmt.Printf("Lock of '%s' has been released\n", info.Owner)
+
+	return nil
+}
+
+func newLock(c *cli.Context) (*action.Lock, error) {
+	client, err := elasticsearch.New(flags.GetElasticSearchOptions(c))
+	if err != nil {
+		return nil, err
+	}
+
+	return action.NewLock(client, flags.GetLockOptions(c)), nil
+}
+
+func printLockInfo(info action.LockInfo) {
+	status := "held"
+	if info.IsExpired() {
+		status = "expired"
+	}
+
+	fmt.Printf("Lock: %s\n", status)
+	fmt.Printf("\tOwner: %s\n", info.Owner)
+	fmt.Printf("\tAcquired at: %s\n", info.AcquiredAt.Format(time.RFC3339))
+	fmt.Printf("\tLast heartbeat at: %s\n", info.HeartbeatAt.Format(time.RFC3339))
+	fmt.Printf("\tExpires at: %s\n", info.ExpiresAt.Format(time.RFC3339))
+}
@@ -0,0 +1,71 @@
+package plan
+
+import (
+	"fmt"
+
+	"github.com/stretchy/stretchy/internal/cmd/common"
+	"github.com/stretchy/stretchy/internal/cmd/flags"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/urfave/cli/v2"
+)
+
+func GetPlanCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "plan",
+		Usage: "Compute the changes and write them to a plan file that can be executed with 'apply --plan'",
+		Flags: flags.Merge(
+			flags.GetConfigurationFlags(),
+			flags.GetElasticSearchFlags(),
+			flags.GetCompareFlags(),
+			flags.GetDiffFlags(),
+			[]cli.Flag{
+				&cli.StringFlag{
+					Name:    "out",
+					Usage:   "Path of the plan file",
+					EnvVars: []string{"PLAN_OUT"},
+					Value:   "stretchy-plan.json",
+				},
+			},
+		),
+		Action: execute,
+	}
+}
+
+func execute(c *cli.Context) error {
+	configDiffOptions, err := flags.GetConfigDiffOptions(c)
+	if err != nil {
+		return err
+	}
+
+	indexCollection, err := common.LoadIndexCollection(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := elasticsearch.New(flags.GetElasticSearchOptions(c))
+	if err != nil {
+		return err
+	}
+
+	compareResultCollection, err := common.Compare(c, indexCollection, client)
+	if err != nil {
+		return err
+	}
+
+	common.PrintCompareResults(compareResultCollection)
+
+	if configDiffOptions != nil {
+		if err := common.PrintConfigDiffs(*configDiffOptions, compareResultCollection); err != nil {
+			return err
+		}
+	}
+
+	if err := action.NewPlan(compareResultCollection).Save(c.String("out")); err != nil {
+		return err
+	}
+
+	fmt.Printf("Plan saved to '%s'\n", c.String("out"))
+
+	return nil
+}
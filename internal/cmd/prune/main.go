@@ -0,0 +1,111 @@
+package prune
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stretchy/stretchy/internal/cmd/common"
+	"github.com/stretchy/stretchy/internal/cmd/flags"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/urfave/cli/v2"
+)
+
+func GetPruneCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "prune",
+		Usage: "Delete, close or mark as read-only the old index versions left behind by migrations",
+		Flags: flags.Merge(
+			flags.GetConfigurationFlags(),
+			flags.GetElasticSearchFlags(),
+			[]cli.Flag{
+				&cli.StringSliceFlag{
+					Name: "index-names",
+				},
+				flags.GetIndexPrefixFlag(),
+				&cli.IntFlag{
+					Name:    "keep",
+					Usage:   "Number of previous versions to keep besides the aliased index",
+					EnvVars: []string{"PRUNE_KEEP"},
+					Value:   2,
+				},
+				&cli.StringFlag{
+					Name:    "mode",
+					Usage:   "What to do with the versions beyond the retention: read-only, close or delete",
+					EnvVars: []string{"PRUNE_MODE"},
+					Value:   action.PruneModeReadOnly.String(),
+				},
+				&cli.BoolFlag{
+					Name:    "dry-run",
+					Usage:   "Only show the versions that would be pruned",
+					EnvVars: []string{"DRY_RUN"},
+				},
+			},
+		),
+		Action: execute,
+	}
+}
+
+func execute(c *cli.Context) error {
+	mode, err := action.NewPruneModeFromString(c.String("mode"))
+	if err != nil {
+		return err
+	}
+
+	indexCollection, err := common.LoadIndexCollection(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := elasticsearch.New(flags.GetElasticSearchOptions(c))
+	if err != nil {
+		return err
+	}
+
+	pruneAction := action.NewPrune(client, c.String("index-prefix"), action.PruneOptions{
+		Keep: c.Int("keep"),
+		Mode: mode,
+	})
+
+	pruneResultCollection, err := pruneAction.PrepareAll(indexCollection)
+	if err != nil {
+		return err
+	}
+
+	printPruneResults(pruneResultCollection, mode)
+
+	if c.Bool("dry-run") {
+		return nil
+	}
+
+	return pruneAction.PruneAll(pruneResultCollection)
+}
+
+func printPruneResults(pruneResultCollection action.PruneResultCollection, mode action.PruneMode) {
+	fmt.Printf("Prune:\n")
+
+	for _, pruneResult := range pruneResultCollection {
+		fmt.Printf("\tAlias '%s' => '%s'\n", pruneResult.AliasName, pruneResult.CurrentIndexName)
+
+		for _, index := range pruneResult.Kept {
+			fmt.Printf("\t\tKEEP => %s\n", index.Name)
+		}
+
+		for _, index := range pruneResult.Pruned {
+			fmt.Printf("\t\t%s => %s\n", strings.ToUpper(mode.String()), index.Name)
+		}
+
+		for _, index := range pruneResult.Orphans {
+			fmt.Printf(
+				"\t\tORPHAN => %s (created at %s, %d docs)\n",
+				index.Name,
+				index.CreationDate.Format("2006-01-02 15:04:05"),
+				index.DocsCount,
+			)
+		}
+
+		for _, index := range pruneResult.Unmanaged {
+			fmt.Printf("\t\tSKIP => %s (not created by stretchy)\n", index.Name)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package rollback
+
+import (
+	"fmt"
+
+	"github.com/stretchy/stretchy/internal/cmd/flags"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/urfave/cli/v2"
+)
+
+func GetRollbackCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "rollback",
+		Usage:     "Point an alias back at a previous version of its index",
+		ArgsUsage: "<alias>",
+		Flags: flags.Merge(
+			flags.GetElasticSearchFlags(),
+			flags.GetHistoryFlags(),
+			flags.GetHistoryRecordFlags(),
+			flags.GetLockFlags(),
+			[]cli.Flag{
+				flags.GetIndexPrefixFlag(),
+				flags.GetTakeLockFlag(),
+				&cli.StringFlag{
+					Name:  "to",
+					Usage: "Index to roll back to, the previous version of the alias by default",
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Show the changes without moving the alias",
+				},
+			},
+		),
+		Action: execute,
+	}
+}
+
+func execute(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected exactly one alias, got %d arguments", c.NArg())
+	}
+
+	client, err := elasticsearch.New(flags.GetElasticSearchOptions(c))
+	if err != nil {
+		return err
+	}
+
+	// A dry run doesn't change anything, it must not block the other jobs.
+	if c.Bool("dry-run") || !c.Bool("lock") {
+		return rollback(c, client, func() error { return nil })
+	}
+
+	lock := action.NewLock(client, flags.GetLockOptions(c))
+	if err := lock.Acquire(); err != nil {
+		return err
+	}
+
+	err = rollback(c, client, lock.Lost)
+
+	if releaseErr := lock.Release(); err == nil {
+		err = releaseErr
+	}
+
+	return err
+}
+
+// rollback moves the alias, checkLock fails when the lock taken for the rollback is lost.
+func rollback(c *cli.Context, client elasticsearch.Client, checkLock func() error) error {
+	aliasName := elasticsearch.ResolveAliasName(c.String("index-prefix"), c.Args().First())
+	history := action.NewHistory(client, flags.GetHistoryOptions(c))
+	rollbackAction := action.NewRollback(client, history)
+
+	rollbackResult, err := rollbackAction.Prepare(aliasName, c.String("to"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(
+		"Rollback:\n\tAlias '%s' => '%s' (currently '%s')\n",
+		rollbackResult.AliasName,
+		rollbackResult.TargetIndexName,
+		rollbackResult.CurrentIndexName,
+	)
+
+	if rollbackResult.TargetWriteBlocked {
+		fmt.Printf("\tIndex '%s' is write-blocked, the block is removed\n", rollbackResult.TargetIndexName)
+	}
+
+	for _, d := range rollbackResult.Changes {
+		fmt.Printf("\t\t%s\n", d.String())
+	}
+
+	if c.Bool("dry-run") {
+		return nil
+	}
+
+	if err := checkLock(); err != nil {
+		return err
+	}
+
+	outcome := rollbackAction.RollbackWithOutcome(rollbackResult)
+
+	if c.Bool("history") {
+		// The alias is moved anyway, a missing record must not turn the rollback into a failure.
+		if historyErr := history.RecordRollback(outcome); historyErr != nil {
+			fmt.Printf("Warning: the history could not be recorded: %s\n", historyErr)
+		}
+	}
+
+	if outcome.Err != nil {
+		return outcome.Err
+	}
+
+	fmt.Printf("Alias '%s' now targets '%s'\n", rollbackResult.AliasName, rollbackResult.TargetIndexName)
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/stretchy/stretchy/internal/cmd/common"
+	"github.com/stretchy/stretchy/internal/cmd/flags"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/urfave/cli/v2"
+)
+
+func GetStatusCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "status",
+		Usage: "Show the aliases of the configured indices, their versions, their health and the pending changes",
+		Flags: flags.Merge(
+			flags.GetConfigurationFlags(),
+			flags.GetElasticSearchFlags(),
+			flags.GetCompareFlags(),
+			[]cli.Flag{
+				&cli.StringFlag{
+					Name:  "output",
+					Usage: "Output format: table or json",
+					Value: "table",
+				},
+			},
+		),
+		Action: execute,
+	}
+}
+
+func execute(c *cli.Context) error {
+	indexCollection, err := common.LoadIndexCollection(c)
+	if err != nil {
+		return err
+	}
+
+	client, err := elasticsearch.New(flags.GetElasticSearchOptions(c))
+	if err != nil {
+		return err
+	}
+
+	indexStatusCollection, err := action.NewStatus(
+		client,
+		c.String("index-prefix"),
+		c.Bool("enable-soft-update"),
+	).StatusAll(indexCollection)
+
+	if err != nil {
+		return err
+	}
+
+	switch c.String("output") {
+	case "table":
+		return printTable(indexStatusCollection)
+	case "json":
+		return printJSON(indexStatusCollection)
+	}
+
+	return fmt.Errorf("unknown output format '%s'", c.String("output"))
+}
+
+func printTable(indexStatusCollection action.IndexStatusCollection) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "ALIAS\tINDEX\tHEALTH\tDOCS\tSIZE\tPREVIOUS VERSIONS\tORPHANS\tPENDING")
+
+	for _, indexStatus := range indexStatusCollection {
+		index, health, docs, size := "-", "-", "-", "-"
+
+		if indexStatus.Index != nil {
+			index = indexStatus.Index.Name
+			health = indexStatus.Index.Health
+			docs = strconv.FormatInt(indexStatus.Index.DocsCount, 10)
+			size = indexStatus.Index.StoreSize
+		}
+
+		pending := indexStatus.PendingAction.String()
+		if indexStatus.Drift {
+			pending += " (drift)"
+		}
+
+		fmt.Fprintf(
+			writer,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			indexStatus.AliasName,
+			index,
+			health,
+			docs,
+			size,
+			indexNames(indexStatus.PreviousVersions),
+			indexNames(indexStatus.Orphans),
+			pending,
+		)
+	}
+
+	return writer.Flush()
+}
+
+func indexNames(indices []elasticsearch.IndexInfo) string {
+	if len(indices) == 0 {
+		return "-"
+	}
+
+	names := make([]string, 0, len(indices))
+	for _, index := range indices {
+		names = append(names, index.Name)
+	}
+
+	return strings.Join(names, ",")
+}
+
+func printJSON(indexStatusCollection action.IndexStatusCollection) error {
+	content, err := json.MarshalIndent(indexStatusCollection, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(content))
+
+	return nil
+}
@@ -2,47 +2,137 @@ package action
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/stretchy/stretchy/pkg/configuration"
 	"github.com/stretchy/stretchy/pkg/elasticsearch"
 	"github.com/stretchy/stretchy/pkg/strategy"
 )
 
+const defaultReindexPollInterval = 5 * time.Second
+const defaultReindexMaxPollErrors = 5
+
+// ApplyOptions is a set of flags to configure an Apply.
+type ApplyOptions struct {
+	ReindexPollInterval time.Duration
+	// ReindexTimeout is how long a reindex task may run before it's cancelled, 0 waits until it's completed.
+	ReindexTimeout time.Duration
+	// ReindexMaxPollErrors is the number of consecutive polls of a reindex task allowed to fail.
+	ReindexMaxPollErrors int
+	OnReindexProgress    func(compareResult CompareResult, task elasticsearch.ReindexTask)
+	Verify               VerifyOptions
+	WriteSafety          WriteSafetyOptions
+	Naming               elasticsearch.NamingStrategy
+	// CheckLock fails when the lock taken for the apply is lost, e.g. Lock.Lost. It is checked before an index is
+	// created or changed and before an alias is swapped, so that the apply stops instead of racing another job.
+	CheckLock func() error
+	// Version and GitSHA are recorded in the metadata of the created and updated indices.
+	Version string
+	GitSHA  string
+}
+
 type Apply struct {
-	client elasticsearch.Client
+	client  elasticsearch.Client
+	options ApplyOptions
 }
 
 func NewApply(
 	client elasticsearch.Client,
+	options ApplyOptions,
 ) *Apply {
+	if options.ReindexPollInterval <= 0 {
+		options.ReindexPollInterval = defaultReindexPollInterval
+	}
+
+	if options.ReindexMaxPollErrors <= 0 {
+		options.ReindexMaxPollErrors = defaultReindexMaxPollErrors
+	}
+
 	return &Apply{
-		client: client,
+		client:  client,
+		options: options,
 	}
 }
 
+// ApplyOutcome describes how a compare result has been applied.
+type ApplyOutcome struct {
+	CompareResult CompareResult
+	// IndexName is the index targeted by the alias once the result is applied.
+	IndexName    string
+	StartedAt    time.Time
+	Duration     time.Duration
+	ReindexTasks []elasticsearch.ReindexTask
+	Err          error
+}
+
+type ApplyOutcomeCollection []ApplyOutcome
+
 func (a *Apply) Apply(compareResult CompareResult) error {
-	switch compareResult.Result.Action() {
-	case strategy.IndexDecisionNone:
+	return a.ApplyWithOutcome(compareResult).Err
+}
+
+// ApplyWithOutcome applies the compare result and reports what has been done, even when it fails.
+func (a *Apply) ApplyWithOutcome(compareResult CompareResult) ApplyOutcome {
+	outcome := ApplyOutcome{
+		CompareResult: compareResult,
+		IndexName:     compareResult.CurrentIndexName,
+		StartedAt:     time.Now(),
+	}
+
+	outcome.Err = a.apply(&outcome)
+	outcome.Duration = time.Now().Sub(outcome.StartedAt)
+
+	return outcome
+}
+
+func (a *Apply) apply(outcome *ApplyOutcome) error {
+	compareResult := outcome.CompareResult
+
+	if compareResult.Result.Action() == strategy.IndexDecisionNone {
 		return nil
+	}
+
+	if err := a.checkLock(); err != nil {
+		return err
+	}
+
+	switch compareResult.Result.Action() {
 	case strategy.IndexDecisionCreate:
-		newIndexName := elasticsearch.CreateIndexName(compareResult.AliasName)
-		if err := a.client.CreateIndex(newIndexName, compareResult.NewConfig); err != nil {
+		newConfig, err := a.newConfigWithMetadata(compareResult)
+		if err != nil {
+			return err
+		}
+
+		newIndexName, err := a.createIndex(compareResult, newConfig)
+		if err != nil {
+			return err
+		}
+
+		outcome.IndexName = newIndexName
+
+		if err := a.recordLiveHash(newIndexName); err != nil {
+			return err
+		}
+
+		if err := a.checkLock(); err != nil {
 			return err
 		}
 
 		return a.client.CreateAlias(compareResult.AliasName, newIndexName)
 	case strategy.IndexDecisionUpdate:
-		return a.client.UpdateIndexConfiguration(compareResult.CurrentIndexName, compareResult.NewConfig)
-	case strategy.IndexDecisionMigrate:
-		newIndexName := elasticsearch.CreateIndexName(compareResult.AliasName)
-		if err := a.client.CreateIndex(newIndexName, compareResult.NewConfig); err != nil {
+		if err := a.update(compareResult); err != nil {
 			return err
 		}
 
-		if err := a.client.Reindex(compareResult.CurrentIndexName, newIndexName); err != nil {
+		return a.recordLiveHash(compareResult.CurrentIndexName)
+	case strategy.IndexDecisionUpdateAnalysis:
+		if err := a.updateAnalysis(compareResult); err != nil {
 			return err
 		}
 
-		return a.client.UpdateAlias(compareResult.AliasName, newIndexName)
+		return a.recordLiveHash(compareResult.CurrentIndexName)
+	case strategy.IndexDecisionMigrate:
+		return a.migrate(outcome)
 	}
 
 	return fmt.Errorf(
@@ -52,12 +142,337 @@ func (a *Apply) Apply(compareResult CompareResult) error {
 	)
 }
 
-func (a *Apply) ApplyAll(compareResultCollection CompareResultCollection) error {
-	for _, compareResult := range compareResultCollection {
-		if err := a.Apply(compareResult); err != nil {
+// update applies the new properties and the changed dynamic settings on the current index.
+// The mappings are always updated, so that the metadata matches the applied configuration.
+func (a *Apply) update(compareResult CompareResult) error {
+	settings := compareResult.Result.Changes().SettingsChanges()
+
+	if len(settings) > 0 {
+		if err := a.client.UpdateIndexSettings(compareResult.CurrentIndexName, settings); err != nil {
 			return err
 		}
 	}
 
+	return a.updateMappings(compareResult)
+}
+
+// updateAnalysis closes the current index while the analysis settings are updated, since they cannot be
+// changed on an open index.
+func (a *Apply) updateAnalysis(compareResult CompareResult) error {
+	settings := compareResult.Result.Changes().SettingsChanges()
+
+	// The whole analysis is sent, a list like the filters of an analyzer would be partially updated otherwise.
+	indexSettings, _ := settings["index"].(map[string]interface{})
+	if indexSettings == nil {
+		indexSettings = map[string]interface{}{}
+		settings["index"] = indexSettings
+	}
+
+	indexSettings["analysis"] = compareResult.NewConfig.GetSettings().GetIndexSettings()["analysis"]
+
+	if err := a.client.CloseIndex(compareResult.CurrentIndexName); err != nil {
+		return err
+	}
+
+	err := a.client.UpdateIndexSettings(compareResult.CurrentIndexName, settings)
+
+	if openErr := a.client.OpenIndex(compareResult.CurrentIndexName); err == nil {
+		err = openErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return a.updateMappings(compareResult)
+}
+
+func (a *Apply) updateMappings(compareResult CompareResult) error {
+	newConfig, err := a.newConfigWithMetadata(compareResult)
+	if err != nil {
+		return err
+	}
+
+	return a.client.UpdateIndexConfiguration(compareResult.CurrentIndexName, newConfig)
+}
+
+// checkLock fails when the lock taken for the apply is lost.
+func (a *Apply) checkLock() error {
+	if a.options.CheckLock == nil {
+		return nil
+	}
+
+	return a.options.CheckLock()
+}
+
+// newConfigWithMetadata records in the new configuration how the index has been created.
+func (a *Apply) newConfigWithMetadata(compareResult CompareResult) (configuration.Index, error) {
+	hash, err := compareResult.NewConfig.Hash()
+	if err != nil {
+		return configuration.Index{}, err
+	}
+
+	return compareResult.NewConfig.WithMetadata(configuration.Metadata{
+		Version:           a.options.Version,
+		ConfigurationName: compareResult.ConfigurationName,
+		ConfigurationHash: hash,
+		AppliedAt:         time.Now(),
+		GitSHA:            a.options.GitSHA,
+	})
+}
+
+// recordLiveHash records the hash of the configuration read back from the applied index, where the server added
+// its defaults. Any later change of the live index, whatever the configuration file, is then reported as a drift,
+// including the fields mapped dynamically when documents are indexed: they are changes of the live mappings too.
+func (a *Apply) recordLiveHash(indexName string) error {
+	liveIndex, err := a.client.GetIndexConfiguration(indexName)
+	if err != nil {
+		return err
+	}
+
+	index, metadata := liveIndex.ExtractMetadata()
+	if metadata == nil {
+		return fmt.Errorf("index '%s' has no stretchy metadata", indexName)
+	}
+
+	if metadata.LiveHash, err = index.Hash(); err != nil {
+		return err
+	}
+
+	indexWithMetadata, err := index.WithMetadata(*metadata)
+	if err != nil {
+		return err
+	}
+
+	return a.client.UpdateIndexConfiguration(indexName, indexWithMetadata)
+}
+
+// createIndex creates the new index named by the naming strategy.
+// With the hash strategy, an index left by a failed attempt to apply the same configuration is reused.
+func (a *Apply) createIndex(compareResult CompareResult, newConfig configuration.Index) (string, error) {
+	newIndexName, err := a.options.Naming.NewIndexName(a.client, compareResult.AliasName, compareResult.NewConfig)
+	if err != nil {
+		return "", err
+	}
+
+	if a.options.Naming != elasticsearch.NamingStrategyHash {
+		return newIndexName, a.client.CreateIndex(newIndexName, newConfig)
+	}
+
+	if newIndexName == compareResult.CurrentIndexName {
+		return "", fmt.Errorf(
+			"index '%s' already has the configuration of alias '%s', it has been changed outside of stretchy",
+			newIndexName,
+			compareResult.AliasName,
+		)
+	}
+
+	exist, err := a.client.IndexExist(newIndexName)
+	if err != nil {
+		return "", err
+	}
+
+	if !exist {
+		return newIndexName, a.client.CreateIndex(newIndexName, newConfig)
+	}
+
+	if err := a.checkFailedAttempt(compareResult, newIndexName); err != nil {
+		return "", err
+	}
+
+	return newIndexName, a.client.UpdateIndexConfiguration(newIndexName, newConfig)
+}
+
+// checkFailedAttempt tells if an existing index of the hash strategy has been left by a failed attempt
+// to apply the same configuration, and can be reused.
+// An index once targeted by the alias has its live hash recorded: it may be a rollback target, so it is never reused.
+func (a *Apply) checkFailedAttempt(compareResult CompareResult, indexName string) error {
+	hash, err := compareResult.NewConfig.Hash()
+	if err != nil {
+		return err
+	}
+
+	index, err := a.client.GetIndexConfiguration(indexName)
+	if err != nil {
+		return err
+	}
+
+	aliasNames, err := a.client.GetIndexAliases(indexName)
+	if err != nil {
+		return err
+	}
+
+	_, metadata := index.ExtractMetadata()
+
+	if metadata == nil ||
+		metadata.ConfigurationName != compareResult.ConfigurationName ||
+		metadata.ConfigurationHash != hash ||
+		metadata.LiveHash != "" ||
+		len(aliasNames) > 0 {
+		return fmt.Errorf(
+			"index '%s' already exists and is not a failed attempt to apply alias '%s', "+
+				"roll back to it or delete it",
+			indexName,
+			compareResult.AliasName,
+		)
+	}
+
+	return nil
+}
+
+func (a *Apply) migrate(outcome *ApplyOutcome) error {
+	compareResult := outcome.CompareResult
+	writeSafety := a.options.WriteSafety
+
+	if writeSafety.Mode == WriteSafetyCatchUp &&
+		!compareResult.CurrentConfig.GetMappings().HasField(writeSafety.TimestampField) {
+		return fmt.Errorf(
+			"cannot catch up the writes on index '%s': timestamp field '%s' is not mapped",
+			compareResult.CurrentIndexName,
+			writeSafety.TimestampField,
+		)
+	}
+
+	// A write block set before the migration, e.g. by an operator, is kept once it ends.
+	writeBlocked := false
+
+	if writeSafety.Mode != WriteSafetyNone {
+		currentConfig, err := a.client.GetIndexConfiguration(compareResult.CurrentIndexName)
+		if err != nil {
+			return err
+		}
+
+		writeBlocked = isWriteBlocked(currentConfig)
+	}
+
+	newConfig, err := a.newConfigWithMetadata(compareResult)
+	if err != nil {
+		return err
+	}
+
+	newIndexName, err := a.createIndex(compareResult, newConfig)
+	if err != nil {
+		return err
+	}
+
+	err = a.moveDocuments(outcome, newIndexName)
+
+	if writeSafety.Mode != WriteSafetyNone && !writeBlocked {
+		if unblockErr := a.client.SetWriteBlock(compareResult.CurrentIndexName, false); err == nil {
+			err = unblockErr
+		}
+	}
+
+	return err
+}
+
+// moveDocuments copies the documents in the new index and moves the alias on it.
+func (a *Apply) moveDocuments(outcome *ApplyOutcome, newIndexName string) error {
+	compareResult := outcome.CompareResult
+	writeSafety := a.options.WriteSafety
+	migrationStart := time.Now()
+
+	if writeSafety.Mode == WriteSafetyBlockWrites {
+		if err := a.client.SetWriteBlock(compareResult.CurrentIndexName, true); err != nil {
+			return err
+		}
+	}
+
+	if err := a.reindex(outcome, func() (string, error) {
+		return a.client.StartReindex(compareResult.CurrentIndexName, newIndexName)
+	}); err != nil {
+		return err
+	}
+
+	if writeSafety.Mode == WriteSafetyCatchUp {
+		if err := a.client.SetWriteBlock(compareResult.CurrentIndexName, true); err != nil {
+			return err
+		}
+
+		if err := a.reindex(outcome, func() (string, error) {
+			return a.client.StartCatchUpReindex(
+				compareResult.CurrentIndexName,
+				newIndexName,
+				writeSafety.TimestampField,
+				migrationStart.Add(-writeSafety.CatchUpMargin),
+			)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := NewVerify(a.client, a.options.Verify).Verify(compareResult.CurrentIndexName, newIndexName); err != nil {
+		return err
+	}
+
+	// The live hash is recorded once the reindex has added the dynamic fields of the documents.
+	if err := a.recordLiveHash(newIndexName); err != nil {
+		return err
+	}
+
+	if err := a.checkLock(); err != nil {
+		return err
+	}
+
+	if err := a.client.UpdateAlias(compareResult.AliasName, newIndexName); err != nil {
+		return err
+	}
+
+	outcome.IndexName = newIndexName
+
 	return nil
 }
+
+// reindex runs a reindex task, and records it in the outcome.
+func (a *Apply) reindex(
+	outcome *ApplyOutcome,
+	startReindex func() (string, error),
+) error {
+	var onProgress elasticsearch.ReindexProgressFunc
+
+	if a.options.OnReindexProgress != nil {
+		onProgress = func(task elasticsearch.ReindexTask) {
+			a.options.OnReindexProgress(outcome.CompareResult, task)
+		}
+	}
+
+	taskID, err := startReindex()
+	if err != nil {
+		return err
+	}
+
+	task, err := elasticsearch.WaitForReindex(a.client, taskID, elasticsearch.ReindexWaitOptions{
+		PollInterval:  a.options.ReindexPollInterval,
+		Timeout:       a.options.ReindexTimeout,
+		MaxPollErrors: a.options.ReindexMaxPollErrors,
+		OnProgress:    onProgress,
+	})
+
+	if task.ID != "" {
+		outcome.ReindexTasks = append(outcome.ReindexTasks, task)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// The alias must not be moved to an index where documents are missing.
+	return task.Validate()
+}
+
+// ApplyAll applies the compare results in order and stops on the first failure.
+// The outcomes include the failed result.
+func (a *Apply) ApplyAll(compareResultCollection CompareResultCollection) (ApplyOutcomeCollection, error) {
+	outcomes := ApplyOutcomeCollection{}
+
+	for _, compareResult := range compareResultCollection {
+		outcome := a.ApplyWithOutcome(compareResult)
+		outcomes = append(outcomes, outcome)
+
+		if outcome.Err != nil {
+			return outcomes, outcome.Err
+		}
+	}
+
+	return outcomes, nil
+}
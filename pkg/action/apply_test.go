@@ -1,6 +1,7 @@
 package action_test
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -22,6 +23,7 @@ const currentUpdateIndexName = "index-update-current"
 
 const migrateAliasName = "index-migrate"
 const currentMigrateIndexName = "index-migrate-current"
+const reindexTaskID = "node:1234"
 
 func TestApply_ApplyAll(t *testing.T) {
 	client := elasticsearch.NewMockClient()
@@ -34,9 +36,11 @@ func TestApply_ApplyAll(t *testing.T) {
 	client.On(
 		"CreateIndex",
 		elasticsearch.CreateIndexName(createAliasName),
-		createConfig(),
+		withMetadata(t, createConfig()),
 	).Return(nil)
 
+	expectLiveHash(t, client, elasticsearch.CreateIndexName(createAliasName), createConfig())
+
 	client.On(
 		"CreateAlias",
 		createAliasName,
@@ -47,30 +51,14 @@ func TestApply_ApplyAll(t *testing.T) {
 	client.On(
 		"UpdateIndexConfiguration",
 		currentUpdateIndexName,
-		updateConfig(),
+		withMetadata(t, updateConfig()),
 	).Return(nil)
 
-	// Migrate Mocks
+	expectLiveHash(t, client, currentUpdateIndexName, updateConfig())
 
-	client.On(
-		"CreateIndex",
-		elasticsearch.CreateIndexName(migrateAliasName),
-		migrateConfig(),
-	).Return(nil)
+	expectMigrate(t, client)
 
-	client.On(
-		"Reindex",
-		currentMigrateIndexName,
-		elasticsearch.CreateIndexName(migrateAliasName),
-	).Return(nil)
-
-	client.On(
-		"UpdateAlias",
-		migrateAliasName,
-		elasticsearch.CreateIndexName(migrateAliasName),
-	).Return(nil)
-
-	applyAction := action.NewApply(client)
+	applyAction := action.NewApply(client, action.ApplyOptions{})
 
 	compareResultCollection := action.CompareResultCollection{
 		action.CompareResult{
@@ -88,20 +76,121 @@ func TestApply_ApplyAll(t *testing.T) {
 			CurrentIndexName: currentUpdateIndexName,
 			Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionUpdate, nil),
 		}, // UPDATE
-		action.CompareResult{
-			AliasName:        migrateAliasName,
-			NewConfig:        migrateConfig(),
-			CurrentIndexName: currentMigrateIndexName,
-			Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionMigrate, nil),
-		}, // MIGRATE
+		migrateCompareResult(), // MIGRATE
 	}
 
-	err := applyAction.ApplyAll(compareResultCollection)
+	outcomes, err := applyAction.ApplyAll(compareResultCollection)
+	assert.NoError(t, err)
+
+	assert.Len(t, outcomes, 4)
+	assert.Equal(t, "", outcomes[0].IndexName)
+	assert.Equal(t, elasticsearch.CreateIndexName(createAliasName), outcomes[1].IndexName)
+	assert.Equal(t, currentUpdateIndexName, outcomes[2].IndexName)
+	assert.Equal(t, elasticsearch.CreateIndexName(migrateAliasName), outcomes[3].IndexName)
+	assert.Len(t, outcomes[3].ReindexTasks, 1)
+
+	for _, outcome := range outcomes {
+		assert.NoError(t, outcome.Err)
+	}
+
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestApply_ApplyAll_ReindexProgress(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	now := time.Now()
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	expectMigrate(t, client)
+
+	progressCalls := 0
+	applyAction := action.NewApply(client, action.ApplyOptions{
+		OnReindexProgress: func(compareResult action.CompareResult, task elasticsearch.ReindexTask) {
+			assert.Equal(t, migrateAliasName, compareResult.AliasName)
+			assert.Equal(t, reindexTaskID, task.ID)
+			progressCalls++
+		},
+	})
+
+	outcomes, err := applyAction.ApplyAll(action.CompareResultCollection{migrateCompareResult()})
 	assert.NoError(t, err)
+	assert.Equal(t, 1, progressCalls)
+	assert.Len(t, outcomes, 1)
+	assert.Len(t, outcomes[0].ReindexTasks, 1)
 
 	mock.AssertExpectationsForObjects(t, client)
 }
 
+func migrateCompareResult() action.CompareResult {
+	return action.CompareResult{
+		AliasName:        migrateAliasName,
+		NewConfig:        migrateConfig(),
+		CurrentIndexName: currentMigrateIndexName,
+		Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionMigrate, nil),
+	}
+}
+
+// expectMigrate mocks the migration of the migrate alias to a new index, time.Now must be patched.
+func expectMigrate(t *testing.T, client *elasticsearch.MockClient) {
+	client.On(
+		"CreateIndex",
+		elasticsearch.CreateIndexName(migrateAliasName),
+		withMetadata(t, migrateConfig()),
+	).Return(nil)
+
+	client.On(
+		"StartReindex",
+		currentMigrateIndexName,
+		elasticsearch.CreateIndexName(migrateAliasName),
+	).Return(reindexTaskID, nil)
+
+	client.On(
+		"GetReindexTask",
+		reindexTaskID,
+	).Return(elasticsearch.ReindexTask{ID: reindexTaskID, Completed: true}, nil)
+
+	expectLiveHash(t, client, elasticsearch.CreateIndexName(migrateAliasName), migrateConfig())
+
+	client.On(
+		"UpdateAlias",
+		migrateAliasName,
+		elasticsearch.CreateIndexName(migrateAliasName),
+	).Return(nil)
+}
+
+// withMetadata returns the configuration sent to the cluster, time.Now must be patched.
+func withMetadata(t *testing.T, config configuration.Index) configuration.Index {
+	hash, err := config.Hash()
+	assert.NoError(t, err)
+
+	index, err := config.WithMetadata(configuration.Metadata{
+		ConfigurationHash: hash,
+		AppliedAt:         time.Now(),
+	})
+	assert.NoError(t, err)
+
+	return index
+}
+
+// expectLiveHash mocks the configuration read back from an applied index, and expects its live hash to be recorded.
+func expectLiveHash(t *testing.T, client *elasticsearch.MockClient, indexName string, config configuration.Index) {
+	hash, err := config.Hash()
+	assert.NoError(t, err)
+
+	appliedIndex := withMetadata(t, config)
+	client.On("GetIndexConfiguration", indexName).Return(appliedIndex, nil)
+
+	_, metadata := appliedIndex.ExtractMetadata()
+	metadata.LiveHash = hash
+
+	recordedIndex, err := config.WithMetadata(*metadata)
+	assert.NoError(t, err)
+
+	client.On("UpdateIndexConfiguration", indexName, recordedIndex).Return(nil)
+}
+
 func createConfig() configuration.Index {
 	return configuration.New(
 		configuration.Mappings{
@@ -134,3 +223,451 @@ func migrateConfig() configuration.Index {
 		},
 	)
 }
+
+func TestApply_Apply_MigrateWithReindexFailures(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	now := time.Now()
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	newIndexName := elasticsearch.CreateIndexName(migrateAliasName)
+
+	client.On("CreateIndex", newIndexName, withMetadata(t, migrateConfig())).Return(nil)
+	client.On("StartReindex", currentMigrateIndexName, newIndexName).Return(reindexTaskID, nil)
+	client.On("GetReindexTask", reindexTaskID).Return(
+		elasticsearch.ReindexTask{
+			ID:        reindexTaskID,
+			Completed: true,
+			Status:    elasticsearch.ReindexStatus{Total: 2, Created: 1},
+			Failures: []elasticsearch.ReindexFailure{
+				{Index: newIndexName, ID: "doc-1", Reason: "mapper_parsing_exception: failed to parse"},
+			},
+		},
+		nil,
+	)
+
+	outcome := action.NewApply(client, action.ApplyOptions{}).ApplyWithOutcome(action.CompareResult{
+		AliasName:        migrateAliasName,
+		NewConfig:        migrateConfig(),
+		CurrentIndexName: currentMigrateIndexName,
+		Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionMigrate, nil),
+	})
+
+	err := outcome.Err
+	assert.IsType(t, &elasticsearch.ReindexError{}, err)
+	assert.Contains(t, err.Error(), "doc-1")
+
+	// The alias still targets the current index.
+	assert.Equal(t, currentMigrateIndexName, outcome.IndexName)
+	assert.Len(t, outcome.ReindexTasks, 1)
+
+	mock.AssertExpectationsForObjects(t, client)
+	client.AssertNotCalled(t, "UpdateAlias", migrateAliasName, newIndexName)
+}
+
+func TestApply_Apply_MigrateWithVerificationFailure(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	now := time.Now()
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	newIndexName := elasticsearch.CreateIndexName(migrateAliasName)
+
+	client.On("CreateIndex", newIndexName, withMetadata(t, migrateConfig())).Return(nil)
+	client.On("StartReindex", currentMigrateIndexName, newIndexName).Return(reindexTaskID, nil)
+	client.On("GetReindexTask", reindexTaskID).Return(
+		elasticsearch.ReindexTask{
+			ID:        reindexTaskID,
+			Completed: true,
+			Status:    elasticsearch.ReindexStatus{Total: 10, Created: 10},
+		},
+		nil,
+	)
+	client.On("RefreshIndex", currentMigrateIndexName).Return(nil)
+	client.On("RefreshIndex", newIndexName).Return(nil)
+	client.On("CountDocuments", currentMigrateIndexName).Return(int64(12), nil)
+	client.On("CountDocuments", newIndexName).Return(int64(10), nil)
+
+	err := action.NewApply(client, action.ApplyOptions{
+		Verify: action.VerifyOptions{CountEnabled: true},
+	}).Apply(action.CompareResult{
+		AliasName:        migrateAliasName,
+		NewConfig:        migrateConfig(),
+		CurrentIndexName: currentMigrateIndexName,
+		Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionMigrate, nil),
+	})
+
+	assert.IsType(t, &action.VerificationError{}, err)
+
+	mock.AssertExpectationsForObjects(t, client)
+	client.AssertNotCalled(t, "UpdateAlias", migrateAliasName, newIndexName)
+}
+
+func TestApply_Apply_UpdateDynamicSettings(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+
+	client.On(
+		"UpdateIndexSettings",
+		currentUpdateIndexName,
+		configuration.Settings{
+			"index": map[string]interface{}{
+				"number_of_replicas": 2,
+				"refresh_interval":   nil,
+			},
+		},
+	).Return(nil)
+	client.On("UpdateIndexConfiguration", currentUpdateIndexName, mock.Anything).Return(nil)
+	client.On("GetIndexConfiguration", currentUpdateIndexName).Return(withMetadata(t, updateConfig()), nil)
+
+	err := action.NewApply(client, action.ApplyOptions{}).Apply(action.CompareResult{
+		AliasName:        updateAliasName,
+		NewConfig:        updateConfig(),
+		CurrentIndexName: currentUpdateIndexName,
+		Result: strategy.NewIndexVoterResult(
+			strategy.IndexDecisionUpdate,
+			configuration.ChangeCollection{
+				{
+					Type: configuration.ChangeTypeUpdate,
+					Path: []string{"settings", "index", "number_of_replicas"},
+					From: "1",
+					To:   2,
+				},
+				{
+					Type: configuration.ChangeTypeDelete,
+					Path: []string{"settings", "index", "refresh_interval"},
+					From: "30s",
+					To:   nil,
+				},
+			},
+		),
+	})
+
+	assert.NoError(t, err)
+
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestApply_Apply_UpdateAnalysis(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+
+	analysis := map[string]interface{}{
+		"analyzer": map[string]interface{}{
+			"folding_analyzer": map[string]interface{}{
+				"tokenizer": "standard",
+				"filter":    []interface{}{"asciifolding"},
+			},
+		},
+	}
+
+	newConfig := configuration.New(
+		configuration.Mappings{},
+		configuration.Settings{"analysis": analysis},
+	)
+
+	client.On("CloseIndex", currentUpdateIndexName).Return(nil)
+	client.On(
+		"UpdateIndexSettings",
+		currentUpdateIndexName,
+		configuration.Settings{
+			"index": map[string]interface{}{
+				"analysis": analysis,
+			},
+		},
+	).Return(nil)
+	client.On("OpenIndex", currentUpdateIndexName).Return(nil)
+	client.On("UpdateIndexConfiguration", currentUpdateIndexName, mock.Anything).Return(nil)
+	client.On("GetIndexConfiguration", currentUpdateIndexName).Return(withMetadata(t, newConfig), nil)
+
+	err := action.NewApply(client, action.ApplyOptions{}).Apply(action.CompareResult{
+		AliasName:        updateAliasName,
+		NewConfig:        newConfig,
+		CurrentIndexName: currentUpdateIndexName,
+		Result: strategy.NewIndexVoterResult(
+			strategy.IndexDecisionUpdateAnalysis,
+			configuration.ChangeCollection{
+				{
+					Type: configuration.ChangeTypeCreate,
+					Path: []string{"settings", "index", "analysis", "analyzer", "folding_analyzer"},
+					To:   analysis["analyzer"].(map[string]interface{})["folding_analyzer"],
+				},
+			},
+		),
+	})
+
+	assert.NoError(t, err)
+
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestApply_Apply_UpdateAnalysisReopensOnError(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+
+	client.On("CloseIndex", currentUpdateIndexName).Return(nil)
+	client.On("UpdateIndexSettings", currentUpdateIndexName, mock.Anything).Return(assert.AnError)
+	client.On("OpenIndex", currentUpdateIndexName).Return(nil)
+
+	err := action.NewApply(client, action.ApplyOptions{}).Apply(action.CompareResult{
+		AliasName:        updateAliasName,
+		NewConfig:        updateConfig(),
+		CurrentIndexName: currentUpdateIndexName,
+		Result: strategy.NewIndexVoterResult(
+			strategy.IndexDecisionUpdateAnalysis,
+			configuration.ChangeCollection{
+				{
+					Type: configuration.ChangeTypeCreate,
+					Path: []string{"settings", "index", "analysis", "filter", "my_filter"},
+					To:   map[string]interface{}{"type": "stop"},
+				},
+			},
+		),
+	})
+
+	assert.Equal(t, assert.AnError, err)
+
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestApply_Apply_NamingStrategy(t *testing.T) {
+	now := time.Now()
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	hash, err := createConfig().Hash()
+	assert.NoError(t, err)
+
+	hashIndexName := createAliasName + "-" + hash[:12]
+
+	testCases := []struct {
+		name              string
+		naming            elasticsearch.NamingStrategy
+		existingIndices   []elasticsearch.IndexInfo
+		indexExist        bool
+		expectedIndexName string
+	}{
+		{
+			name:   "version",
+			naming: elasticsearch.NamingStrategyVersion,
+			existingIndices: []elasticsearch.IndexInfo{
+				{Name: createAliasName + "-v1"},
+				{Name: createAliasName + "-v2"},
+			},
+			expectedIndexName: createAliasName + "-v3",
+		},
+		{
+			name:              "hash",
+			naming:            elasticsearch.NamingStrategyHash,
+			indexExist:        false,
+			expectedIndexName: hashIndexName,
+		},
+		{
+			name:              "hash of an existing index",
+			naming:            elasticsearch.NamingStrategyHash,
+			indexExist:        true,
+			expectedIndexName: hashIndexName,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := elasticsearch.NewMockClient()
+
+			switch {
+			case tc.naming == elasticsearch.NamingStrategyVersion:
+				client.On("ListIndices", createAliasName+"-*").Return(tc.existingIndices, nil)
+				client.On("CreateIndex", tc.expectedIndexName, withMetadata(t, createConfig())).Return(nil)
+			case tc.indexExist:
+				client.On("IndexExist", tc.expectedIndexName).Return(true, nil)
+				client.On("GetIndexConfiguration", tc.expectedIndexName).Return(withMetadata(t, createConfig()), nil).Once()
+				client.On("GetIndexAliases", tc.expectedIndexName).Return([]string{}, nil)
+				client.On("UpdateIndexConfiguration", tc.expectedIndexName, withMetadata(t, createConfig())).Return(nil)
+			default:
+				client.On("IndexExist", tc.expectedIndexName).Return(false, nil)
+				client.On("CreateIndex", tc.expectedIndexName, withMetadata(t, createConfig())).Return(nil)
+			}
+
+			expectLiveHash(t, client, tc.expectedIndexName, createConfig())
+			client.On("CreateAlias", createAliasName, tc.expectedIndexName).Return(nil)
+
+			err := action.NewApply(client, action.ApplyOptions{Naming: tc.naming}).Apply(action.CompareResult{
+				AliasName: createAliasName,
+				NewConfig: createConfig(),
+				Result:    strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
+			})
+
+			assert.NoError(t, err)
+
+			mock.AssertExpectationsForObjects(t, client)
+		})
+	}
+}
+
+func TestApply_Apply_HashNamingOfTheCurrentIndex(t *testing.T) {
+	hash, err := migrateConfig().Hash()
+	assert.NoError(t, err)
+
+	currentIndexName := migrateAliasName + "-" + hash[:12]
+
+	client := elasticsearch.NewMockClient()
+
+	err = action.NewApply(client, action.ApplyOptions{Naming: elasticsearch.NamingStrategyHash}).Apply(
+		action.CompareResult{
+			AliasName:        migrateAliasName,
+			CurrentIndexName: currentIndexName,
+			NewConfig:        migrateConfig(),
+			Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionMigrate, nil),
+		},
+	)
+
+	assert.EqualError(
+		t,
+		err,
+		"index '"+currentIndexName+"' already has the configuration of alias 'index-migrate', "+
+			"it has been changed outside of stretchy",
+	)
+
+	client.AssertNotCalled(t, "CreateIndex", mock.Anything, mock.Anything)
+}
+
+func TestApply_Apply_HashNamingOfAnEarlierTarget(t *testing.T) {
+	hash, err := migrateConfig().Hash()
+	assert.NoError(t, err)
+
+	newIndexName := migrateAliasName + "-" + hash[:12]
+
+	earlierTarget, err := migrateConfig().WithMetadata(configuration.Metadata{
+		ConfigurationHash: hash,
+		LiveHash:          hash,
+	})
+	assert.NoError(t, err)
+
+	failedAttempt, err := migrateConfig().WithMetadata(configuration.Metadata{ConfigurationHash: hash})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		index      configuration.Index
+		aliasNames []string
+	}{
+		{
+			name:       "index with a live hash",
+			index:      earlierTarget,
+			aliasNames: []string{},
+		},
+		{
+			name:       "aliased index",
+			index:      failedAttempt,
+			aliasNames: []string{"another-alias"},
+		},
+		{
+			name:       "index without metadata",
+			index:      migrateConfig(),
+			aliasNames: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := elasticsearch.NewMockClient()
+			client.On("IndexExist", newIndexName).Return(true, nil)
+			client.On("GetIndexConfiguration", newIndexName).Return(tc.index, nil)
+			client.On("GetIndexAliases", newIndexName).Return(tc.aliasNames, nil)
+
+			err := action.NewApply(client, action.ApplyOptions{Naming: elasticsearch.NamingStrategyHash}).Apply(
+				action.CompareResult{
+					AliasName:        migrateAliasName,
+					CurrentIndexName: migrateAliasName + "-current",
+					NewConfig:        migrateConfig(),
+					Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionMigrate, nil),
+				},
+			)
+
+			assert.EqualError(
+				t,
+				err,
+				"index '"+newIndexName+"' already exists and is not a failed attempt to apply alias 'index-migrate', "+
+					"roll back to it or delete it",
+			)
+
+			client.AssertNotCalled(t, "UpdateIndexConfiguration", mock.Anything, mock.Anything)
+			client.AssertNotCalled(t, "Reindex", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestApply_Apply_LockLost(t *testing.T) {
+	lostErr := errors.New("lock of 'ci-job-1' has been released, or taken over, by another job")
+
+	testCases := []struct {
+		name       string
+		lockChecks int
+		result     action.CompareResult
+	}{
+		{
+			name:       "before creating the index",
+			lockChecks: 0,
+			result: action.CompareResult{
+				AliasName: createAliasName,
+				NewConfig: createConfig(),
+				Result:    strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
+			},
+		},
+		{
+			name:       "before creating the alias",
+			lockChecks: 1,
+			result: action.CompareResult{
+				AliasName: createAliasName,
+				NewConfig: createConfig(),
+				Result:    strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
+			},
+		},
+		{
+			name:       "before swapping the alias",
+			lockChecks: 1,
+			result: action.CompareResult{
+				AliasName:        migrateAliasName,
+				CurrentIndexName: currentMigrateIndexName,
+				NewConfig:        migrateConfig(),
+				Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionMigrate, nil),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := elasticsearch.NewMockClient()
+			client.On("CreateIndex", mock.Anything, mock.Anything).Return(nil)
+			client.On("StartReindex", currentMigrateIndexName, mock.Anything).Return(reindexTaskID, nil)
+			client.On("GetReindexTask", reindexTaskID).Return(elasticsearch.ReindexTask{ID: reindexTaskID, Completed: true}, nil)
+			client.On("GetIndexConfiguration", mock.Anything).Return(withMetadata(t, createConfig()), nil)
+			client.On("UpdateIndexConfiguration", mock.Anything, mock.Anything).Return(nil)
+
+			lockChecks := 0
+			checkLock := func() error {
+				if lockChecks == tc.lockChecks {
+					return lostErr
+				}
+
+				lockChecks++
+
+				return nil
+			}
+
+			err := action.NewApply(client, action.ApplyOptions{CheckLock: checkLock}).Apply(tc.result)
+
+			assert.Equal(t, lostErr, err)
+
+			if tc.lockChecks == 0 {
+				client.AssertNotCalled(t, "CreateIndex", mock.Anything, mock.Anything)
+			}
+
+			client.AssertNotCalled(t, "CreateAlias", mock.Anything, mock.Anything)
+			client.AssertNotCalled(t, "UpdateAlias", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -13,11 +13,14 @@ type Compare struct {
 }
 
 type CompareResult struct {
-	AliasName        string
-	CurrentIndexName string
-	CurrentConfig    configuration.Index
-	NewConfig        configuration.Index
-	Result           strategy.IndexVoterResult
+	ConfigurationName string                    `json:"configuration_name"`
+	AliasName         string                    `json:"alias_name"`
+	CurrentIndexName  string                    `json:"current_index_name"`
+	CurrentConfig     configuration.Index       `json:"current_config"`
+	NewConfig         configuration.Index       `json:"new_config"`
+	Result            strategy.IndexVoterResult `json:"result"`
+	// Drift is set when the live index has changed since stretchy applied it.
+	Drift bool `json:"drift"`
 }
 
 type CompareResultCollection []CompareResult
@@ -49,11 +52,12 @@ func (c *Compare) Compare(indexName string, index configuration.Index) (CompareR
 		}
 
 		return CompareResult{
-			AliasName:        aliasName,
-			CurrentIndexName: "",
-			CurrentConfig:    configuration.Index{},
-			NewConfig:        index,
-			Result:           action,
+			ConfigurationName: indexName,
+			AliasName:         aliasName,
+			CurrentIndexName:  "",
+			CurrentConfig:     configuration.Index{},
+			NewConfig:         index,
+			Result:            action,
 		}, nil
 	}
 
@@ -62,25 +66,67 @@ func (c *Compare) Compare(indexName string, index configuration.Index) (CompareR
 		return CompareResult{}, err
 	}
 
-	currentIndex, err := c.client.GetIndexConfiguration(currentIndexName)
+	liveIndex, err := c.client.GetIndexConfiguration(currentIndexName)
 	if err != nil {
 		return CompareResult{}, err
 	}
 
+	currentIndex, metadata := liveIndex.ExtractMetadata()
+
 	action, err := c.indexActionVoter.Compare(&currentIndex, &index)
 	if err != nil {
 		return CompareResult{}, err
 	}
 
+	drift, err := isDrift(metadata, currentIndex, index, action)
+	if err != nil {
+		return CompareResult{}, err
+	}
+
 	return CompareResult{
-		AliasName:        aliasName,
-		CurrentIndexName: currentIndexName,
-		CurrentConfig:    currentIndex,
-		NewConfig:        index,
-		Result:           action,
+		ConfigurationName: indexName,
+		AliasName:         aliasName,
+		CurrentIndexName:  currentIndexName,
+		CurrentConfig:     currentIndex,
+		NewConfig:         index,
+		Result:            action,
+		Drift:             drift,
 	}, nil
 }
 
+// isDrift tells if the live index has changed since stretchy applied it, whatever the configuration file.
+// Without a recorded live hash, the changes come from the live index when the configuration is the one applied.
+func isDrift(
+	metadata *configuration.Metadata,
+	currentIndex configuration.Index,
+	index configuration.Index,
+	result strategy.IndexVoterResult,
+) (bool, error) {
+	if metadata == nil {
+		return false, nil
+	}
+
+	if metadata.LiveHash != "" {
+		liveHash, err := currentIndex.Hash()
+		if err != nil {
+			return false, err
+		}
+
+		return liveHash != metadata.LiveHash, nil
+	}
+
+	if len(result.Changes()) == 0 {
+		return false, nil
+	}
+
+	hash, err := index.Hash()
+	if err != nil {
+		return false, err
+	}
+
+	return hash == metadata.ConfigurationHash, nil
+}
+
 func (c *Compare) CompareAll(indexCollection configuration.IndexCollection) (CompareResultCollection, error) {
 	compareResultCollection := CompareResultCollection{}
 
@@ -95,3 +141,16 @@ func (c *Compare) CompareAll(indexCollection configuration.IndexCollection) (Com
 
 	return compareResultCollection, nil
 }
+
+// HasAction tells if applying the results would take one of the actions.
+func (crc CompareResultCollection) HasAction(actions ...strategy.IndexAction) bool {
+	for _, compareResult := range crc {
+		for _, action := range actions {
+			if compareResult.Result.Action() == action {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,155 @@
+package action
+
+import (
+	"sort"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+// ClusterCompareResult compares the index targeted by an alias on two clusters.
+type ClusterCompareResult struct {
+	AliasName string
+	// SourceIndexName and TargetIndexName are empty when the alias doesn't exist on the cluster.
+	SourceIndexName string
+	TargetIndexName string
+	// Changes turn the source configuration into the target one.
+	Changes configuration.ChangeCollection
+}
+
+// IsMissing tells if the alias only exists on one of the clusters.
+func (ccr ClusterCompareResult) IsMissing() bool {
+	return (ccr.SourceIndexName == "") != (ccr.TargetIndexName == "")
+}
+
+// HasDrift tells if the clusters don't match.
+func (ccr ClusterCompareResult) HasDrift() bool {
+	return ccr.IsMissing() || len(ccr.Changes) > 0
+}
+
+type ClusterCompareResultCollection []ClusterCompareResult
+
+func (ccrc ClusterCompareResultCollection) HasDrift() bool {
+	for _, clusterCompareResult := range ccrc {
+		if clusterCompareResult.HasDrift() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CompareClusters compares the indices of two clusters, e.g. staging and production.
+type CompareClusters struct {
+	source elasticsearch.Client
+	target elasticsearch.Client
+}
+
+func NewCompareClusters(
+	source elasticsearch.Client,
+	target elasticsearch.Client,
+) *CompareClusters {
+	return &CompareClusters{
+		source: source,
+		target: target,
+	}
+}
+
+// ResolveAliases returns the aliases matching the pattern on any of the clusters, sorted by name.
+func (cc *CompareClusters) ResolveAliases(pattern string) ([]string, error) {
+	sourceAliases, err := cc.source.ListAliases(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	targetAliases, err := cc.target.ListAliases(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	aliasNames := []string{}
+
+	for aliasName := range sourceAliases {
+		aliasNames = append(aliasNames, aliasName)
+	}
+
+	for aliasName := range targetAliases {
+		if _, exist := sourceAliases[aliasName]; !exist {
+			aliasNames = append(aliasNames, aliasName)
+		}
+	}
+
+	sort.Strings(aliasNames)
+
+	return aliasNames, nil
+}
+
+func (cc *CompareClusters) Compare(aliasName string) (ClusterCompareResult, error) {
+	sourceIndexName, sourceConfig, err := getAliasedConfiguration(cc.source, aliasName)
+	if err != nil {
+		return ClusterCompareResult{}, err
+	}
+
+	targetIndexName, targetConfig, err := getAliasedConfiguration(cc.target, aliasName)
+	if err != nil {
+		return ClusterCompareResult{}, err
+	}
+
+	clusterCompareResult := ClusterCompareResult{
+		AliasName:       aliasName,
+		SourceIndexName: sourceIndexName,
+		TargetIndexName: targetIndexName,
+		Changes:         configuration.ChangeCollection{},
+	}
+
+	if sourceIndexName == "" || targetIndexName == "" {
+		return clusterCompareResult, nil
+	}
+
+	changes, err := sourceConfig.Diff(targetConfig)
+	if err != nil {
+		return ClusterCompareResult{}, err
+	}
+
+	clusterCompareResult.Changes = changes
+
+	return clusterCompareResult, nil
+}
+
+func (cc *CompareClusters) CompareAll(aliasNames []string) (ClusterCompareResultCollection, error) {
+	clusterCompareResultCollection := ClusterCompareResultCollection{}
+
+	for _, aliasName := range aliasNames {
+		clusterCompareResult, err := cc.Compare(aliasName)
+		if err != nil {
+			return nil, err
+		}
+
+		clusterCompareResultCollection = append(clusterCompareResultCollection, clusterCompareResult)
+	}
+
+	return clusterCompareResultCollection, nil
+}
+
+// getAliasedConfiguration returns the index targeted by the alias and its configuration, without the metadata
+// recorded by stretchy since it differs from a cluster to the other. The index name is empty without alias.
+func getAliasedConfiguration(client elasticsearch.Client, aliasName string) (string, configuration.Index, error) {
+	aliasExist, err := client.AliasExist(aliasName)
+	if err != nil || !aliasExist {
+		return "", configuration.Index{}, err
+	}
+
+	indexName, err := client.GetAliasedIndex(aliasName)
+	if err != nil {
+		return "", configuration.Index{}, err
+	}
+
+	liveIndex, err := client.GetIndexConfiguration(indexName)
+	if err != nil {
+		return "", configuration.Index{}, err
+	}
+
+	index, _ := liveIndex.ExtractMetadata()
+
+	return indexName, index, nil
+}
@@ -0,0 +1,101 @@
+package action_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+func TestCompareClusters_CompareAll(t *testing.T) {
+	source := elasticsearch.NewMockClient()
+	target := elasticsearch.NewMockClient()
+
+	source.On("AliasExist", "stretchy-products").Return(true, nil)
+	source.On("GetAliasedIndex", "stretchy-products").Return("stretchy-products-100", nil)
+	source.On("GetIndexConfiguration", "stretchy-products-100").Return(getConfiguration1(), nil)
+	target.On("AliasExist", "stretchy-products").Return(true, nil)
+	target.On("GetAliasedIndex", "stretchy-products").Return("stretchy-products-200", nil)
+	target.On("GetIndexConfiguration", "stretchy-products-200").Return(getConfiguration2(), nil)
+
+	source.On("AliasExist", "stretchy-orders").Return(true, nil)
+	source.On("GetAliasedIndex", "stretchy-orders").Return("stretchy-orders-100", nil)
+	source.On("GetIndexConfiguration", "stretchy-orders-100").Return(getConfiguration1(), nil)
+	target.On("AliasExist", "stretchy-orders").Return(false, nil)
+
+	// The metadata recorded by stretchy differs from a cluster to the other.
+	sourceUsers, err := getConfiguration1().WithMetadata(configuration.Metadata{GitSHA: "abc"})
+	assert.NoError(t, err)
+
+	targetUsers, err := getConfiguration1().WithMetadata(configuration.Metadata{GitSHA: "def"})
+	assert.NoError(t, err)
+
+	source.On("AliasExist", "stretchy-users").Return(true, nil)
+	source.On("GetAliasedIndex", "stretchy-users").Return("stretchy-users-100", nil)
+	source.On("GetIndexConfiguration", "stretchy-users-100").Return(sourceUsers, nil)
+	target.On("AliasExist", "stretchy-users").Return(true, nil)
+	target.On("GetAliasedIndex", "stretchy-users").Return("stretchy-users-100", nil)
+	target.On("GetIndexConfiguration", "stretchy-users-100").Return(targetUsers, nil)
+
+	clusterCompareResultCollection, err := action.NewCompareClusters(source, target).CompareAll(
+		[]string{"stretchy-products", "stretchy-orders", "stretchy-users"},
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		action.ClusterCompareResultCollection{
+			{
+				AliasName:       "stretchy-products",
+				SourceIndexName: "stretchy-products-100",
+				TargetIndexName: "stretchy-products-200",
+				Changes: configuration.ChangeCollection{
+					{
+						Type: configuration.ChangeTypeCreate,
+						Path: []string{"mappings", "properties", "updated_at"},
+						To:   map[string]interface{}{"type": "date"},
+					},
+				},
+			},
+			{
+				AliasName:       "stretchy-orders",
+				SourceIndexName: "stretchy-orders-100",
+				Changes:         configuration.ChangeCollection{},
+			},
+			{
+				AliasName:       "stretchy-users",
+				SourceIndexName: "stretchy-users-100",
+				TargetIndexName: "stretchy-users-100",
+				Changes:         configuration.ChangeCollection{},
+			},
+		},
+		clusterCompareResultCollection,
+	)
+
+	assert.True(t, clusterCompareResultCollection[0].HasDrift())
+	assert.True(t, clusterCompareResultCollection[1].IsMissing())
+	assert.True(t, clusterCompareResultCollection[1].HasDrift())
+	assert.False(t, clusterCompareResultCollection[2].HasDrift())
+	assert.True(t, clusterCompareResultCollection.HasDrift())
+	assert.False(t, clusterCompareResultCollection[2:].HasDrift())
+}
+
+func TestCompareClusters_ResolveAliases(t *testing.T) {
+	source := elasticsearch.NewMockClient()
+	target := elasticsearch.NewMockClient()
+
+	source.On("ListAliases", "stretchy-*").Return(
+		map[string]string{"stretchy-products": "stretchy-products-100", "stretchy-orders": "stretchy-orders-100"},
+		nil,
+	)
+	target.On("ListAliases", "stretchy-*").Return(
+		map[string]string{"stretchy-products": "stretchy-products-200", "stretchy-users": "stretchy-users-100"},
+		nil,
+	)
+
+	aliasNames, err := action.NewCompareClusters(source, target).ResolveAliases("stretchy-*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"stretchy-orders", "stretchy-products", "stretchy-users"}, aliasNames)
+}
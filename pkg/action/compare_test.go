@@ -99,11 +99,12 @@ func TestCompare_Compare_AliasDoesNotExist(t *testing.T) {
 	assert.Equal(
 		t,
 		action.CompareResult{
-			AliasName:        elasticsearch.ResolveAliasName(prefix, indexName1),
-			CurrentIndexName: "",
-			CurrentConfig:    configuration.Index{},
-			NewConfig:        getConfiguration1(),
-			Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
+			ConfigurationName: indexName1,
+			AliasName:         elasticsearch.ResolveAliasName(prefix, indexName1),
+			CurrentIndexName:  "",
+			CurrentConfig:     configuration.Index{},
+			NewConfig:         getConfiguration1(),
+			Result:            strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
 		},
 		compareResult,
 	)
@@ -147,18 +148,20 @@ func TestCompare_CompareAll(t *testing.T) {
 		t,
 		action.CompareResultCollection{
 			action.CompareResult{
-				AliasName:        elasticsearch.ResolveAliasName(prefix, indexName1),
-				CurrentIndexName: "",
-				CurrentConfig:    configuration.Index{},
-				NewConfig:        getConfiguration1(),
-				Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
+				ConfigurationName: indexName1,
+				AliasName:         elasticsearch.ResolveAliasName(prefix, indexName1),
+				CurrentIndexName:  "",
+				CurrentConfig:     configuration.Index{},
+				NewConfig:         getConfiguration1(),
+				Result:            strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
 			},
 			action.CompareResult{
-				AliasName:        elasticsearch.ResolveAliasName(prefix, indexName2),
-				CurrentIndexName: "",
-				CurrentConfig:    configuration.Index{},
-				NewConfig:        getConfiguration2(),
-				Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
+				ConfigurationName: indexName2,
+				AliasName:         elasticsearch.ResolveAliasName(prefix, indexName2),
+				CurrentIndexName:  "",
+				CurrentConfig:     configuration.Index{},
+				NewConfig:         getConfiguration2(),
+				Result:            strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
 			},
 		},
 		compareResult,
@@ -209,12 +212,133 @@ func TestCompare_Compare_AliasExist(t *testing.T) {
 	assert.Equal(
 		t,
 		action.CompareResult{
-			AliasName:        elasticsearch.ResolveAliasName(prefix, indexName1),
-			CurrentIndexName: aliasedIndexName,
-			CurrentConfig:    getConfiguration1(),
-			NewConfig:        getConfiguration1(),
-			Result:           strategy.NewIndexVoterResult(strategy.IndexDecisionNone, nil),
+			ConfigurationName: indexName1,
+			AliasName:         elasticsearch.ResolveAliasName(prefix, indexName1),
+			CurrentIndexName:  aliasedIndexName,
+			CurrentConfig:     getConfiguration1(),
+			NewConfig:         getConfiguration1(),
+			Result:            strategy.NewIndexVoterResult(strategy.IndexDecisionNone, nil),
 		},
 		compareResult,
 	)
 }
+
+func TestCompare_Compare_Drift(t *testing.T) {
+	metadata := appliedMetadata(t)
+
+	// The applied configuration has been changed by hand on the cluster.
+	handEditedIndex, err := getConfiguration2().WithMetadata(metadata)
+	assert.NoError(t, err)
+
+	appliedIndex, err := getConfiguration1().WithMetadata(metadata)
+	assert.NoError(t, err)
+
+	testCases := []driftTestCase{
+		{
+			name:          "live index changed",
+			liveIndex:     handEditedIndex,
+			newConfig:     getConfiguration1(),
+			expectedDrift: true,
+		},
+		{
+			name:          "configuration changed",
+			liveIndex:     appliedIndex,
+			newConfig:     getConfiguration2(),
+			expectedDrift: false,
+		},
+		{
+			name:          "index without metadata",
+			liveIndex:     getConfiguration2(),
+			newConfig:     getConfiguration1(),
+			expectedDrift: false,
+		},
+	}
+
+	runDriftTestCases(t, testCases)
+}
+
+func TestCompare_Compare_Drift_LiveHash(t *testing.T) {
+	appliedHash, err := getConfiguration1().Hash()
+	assert.NoError(t, err)
+
+	// The live configuration read back after the apply is recorded, whatever the configuration file.
+	recordedMetadata := appliedMetadata(t)
+	recordedMetadata.ConfigurationHash = "another-configuration-hash"
+	recordedMetadata.LiveHash = appliedHash
+
+	recordedIndex, err := getConfiguration1().WithMetadata(recordedMetadata)
+	assert.NoError(t, err)
+
+	handEditedRecordedIndex, err := getConfiguration2().WithMetadata(recordedMetadata)
+	assert.NoError(t, err)
+
+	testCases := []driftTestCase{
+		{
+			name:          "live index changed since the recorded live hash",
+			liveIndex:     handEditedRecordedIndex,
+			newConfig:     getConfiguration1(),
+			expectedDrift: true,
+		},
+		{
+			name:          "configuration changed since the recorded live hash",
+			liveIndex:     recordedIndex,
+			newConfig:     getConfiguration2(),
+			expectedDrift: false,
+		},
+	}
+
+	runDriftTestCases(t, testCases)
+}
+
+type driftTestCase struct {
+	name          string
+	liveIndex     configuration.Index
+	newConfig     configuration.Index
+	expectedDrift bool
+}
+
+// appliedMetadata is the metadata of an index where the first configuration has been applied.
+func appliedMetadata(t *testing.T) configuration.Metadata {
+	appliedHash, err := getConfiguration1().Hash()
+	assert.NoError(t, err)
+
+	return configuration.Metadata{
+		ConfigurationName: indexName1,
+		ConfigurationHash: appliedHash,
+	}
+}
+
+func runDriftTestCases(t *testing.T, testCases []driftTestCase) {
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			aliasName := elasticsearch.ResolveAliasName(prefix, indexName1)
+
+			client := elasticsearch.NewMockClient()
+			client.On("AliasExist", aliasName).Return(true, nil)
+			client.On("GetAliasedIndex", aliasName).Return("aliased-index", nil)
+			client.On("GetIndexConfiguration", "aliased-index").Return(tc.liveIndex, nil)
+
+			compareResult, err := action.NewCompare(client, prefix, true).Compare(indexName1, tc.newConfig)
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, compareResult.Result.Changes())
+			assert.Equal(t, tc.expectedDrift, compareResult.Drift)
+
+			_, currentMetadata := compareResult.CurrentConfig.ExtractMetadata()
+			assert.Nil(t, currentMetadata)
+		})
+	}
+}
+
+func TestCompareResultCollection_HasAction(t *testing.T) {
+	compareResultCollection := action.CompareResultCollection{
+		{Result: strategy.NewIndexVoterResult(strategy.IndexDecisionNone, nil)},
+		{Result: strategy.NewIndexVoterResult(strategy.IndexDecisionUpdate, nil)},
+	}
+
+	assert.True(t, compareResultCollection.HasAction(strategy.IndexDecisionUpdate))
+	assert.True(t, compareResultCollection.HasAction(strategy.IndexDecisionMigrate, strategy.IndexDecisionUpdate))
+	assert.False(t, compareResultCollection.HasAction(strategy.IndexDecisionMigrate, strategy.IndexDecisionCreate))
+	assert.False(t, action.CompareResultCollection{}.HasAction(strategy.IndexDecisionNone))
+}
@@ -0,0 +1,358 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/linediff"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultConfigDiffWidth = 160
+const minConfigDiffColumnWidth = 20
+
+const (
+	colorReset = "\x1b[0m"
+	colorBold  = "\x1b[1m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+)
+
+// ConfigDiffLayout is how the current and new configurations are laid out.
+type ConfigDiffLayout int
+
+const (
+	ConfigDiffUnified ConfigDiffLayout = iota
+	ConfigDiffSideBySide
+)
+
+func (cdl ConfigDiffLayout) String() string {
+	return configDiffLayoutNames()[cdl]
+}
+
+func configDiffLayoutNames() []string {
+	return []string{"unified", "side-by-side"}
+}
+
+func NewConfigDiffLayoutFromString(layout string) (ConfigDiffLayout, error) {
+	for i, name := range configDiffLayoutNames() {
+		if name == layout {
+			return ConfigDiffLayout(i), nil
+		}
+	}
+
+	return ConfigDiffUnified, fmt.Errorf("unknown diff layout '%s'", layout)
+}
+
+// ConfigDiffSyntax is how the configurations are rendered before being compared.
+type ConfigDiffSyntax int
+
+const (
+	ConfigDiffYAML ConfigDiffSyntax = iota
+	ConfigDiffJSON
+)
+
+func (cds ConfigDiffSyntax) String() string {
+	return configDiffSyntaxNames()[cds]
+}
+
+func configDiffSyntaxNames() []string {
+	return []string{"yaml", "json"}
+}
+
+func NewConfigDiffSyntaxFromString(syntax string) (ConfigDiffSyntax, error) {
+	for i, name := range configDiffSyntaxNames() {
+		if name == syntax {
+			return ConfigDiffSyntax(i), nil
+		}
+	}
+
+	return ConfigDiffYAML, fmt.Errorf("unknown diff syntax '%s'", syntax)
+}
+
+// ConfigDiffOptions is a set of flags to configure a ConfigDiff.
+type ConfigDiffOptions struct {
+	Layout ConfigDiffLayout
+	Syntax ConfigDiffSyntax
+	// Context is the number of unchanged lines shown around the changes.
+	Context int
+	// Width is the width of the side-by-side layout, both configurations get half of it.
+	Width int
+	Color bool
+}
+
+// ConfigDiff renders the whole current and new configurations of an index, sorted by key,
+// and prints the difference between them. It gives the context the list of changes lacks.
+type ConfigDiff struct {
+	options ConfigDiffOptions
+}
+
+func NewConfigDiff(options ConfigDiffOptions) *ConfigDiff {
+	if options.Context < 0 {
+		options.Context = 0
+	}
+
+	if options.Width <= 0 {
+		options.Width = defaultConfigDiffWidth
+	}
+
+	return &ConfigDiff{
+		options: options,
+	}
+}
+
+// configDiffHunk is a group of changed lines with their context, the lines start at 1.
+type configDiffHunk struct {
+	fromStart int
+	toStart   int
+	diffs     []linediff.Diff
+}
+
+func (cd *ConfigDiff) Render(compareResult CompareResult) (string, error) {
+	currentLines := []string{}
+	newConfig := compareResult.NewConfig
+
+	// Without alias there is no current configuration, the empty one would be rendered otherwise.
+	if compareResult.CurrentIndexName != "" {
+		lines, err := cd.renderIndex(compareResult.CurrentConfig)
+		if err != nil {
+			return "", err
+		}
+
+		currentLines = lines
+
+		// The differences which aren't changes, e.g. the defaults added by the cluster, aren't shown either.
+		newConfig, err = newConfig.WithUnreportedChanges(compareResult.CurrentConfig)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	newLines, err := cd.renderIndex(newConfig)
+	if err != nil {
+		return "", err
+	}
+
+	hunks := cd.hunks(linediff.Lines(currentLines, newLines))
+	if len(hunks) == 0 {
+		return "", nil
+	}
+
+	currentName := compareResult.CurrentIndexName
+	if currentName == "" {
+		currentName = "/dev/null"
+	}
+
+	output := &strings.Builder{}
+
+	output.WriteString(cd.color(colorBold, fmt.Sprintf("--- %s (current)", currentName)) + "\n")
+	output.WriteString(cd.color(colorBold, fmt.Sprintf("+++ %s (new)", compareResult.AliasName)) + "\n")
+
+	for _, hunk := range hunks {
+		if cd.options.Layout == ConfigDiffSideBySide {
+			cd.writeSideBySideHunk(output, hunk)
+		} else {
+			cd.writeUnifiedHunk(output, hunk)
+		}
+	}
+
+	return output.String(), nil
+}
+
+func (cd *ConfigDiff) renderIndex(index configuration.Index) ([]string, error) {
+	content := &strings.Builder{}
+
+	// Both encoders sort the keys of the maps.
+	if cd.options.Syntax == ConfigDiffJSON {
+		encoder := json.NewEncoder(content)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(index); err != nil {
+			return nil, err
+		}
+	} else {
+		encoder := yaml.NewEncoder(content)
+		encoder.SetIndent(2)
+
+		if err := encoder.Encode(index); err != nil {
+			return nil, err
+		}
+
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	return strings.Split(strings.TrimSuffix(content.String(), "\n"), "\n"), nil
+}
+
+// hunks groups the changed lines with their context, the changes closer than twice the context share a hunk.
+func (cd *ConfigDiff) hunks(diffs []linediff.Diff) []configDiffHunk {
+	hunks := []configDiffHunk{}
+	context := cd.options.Context
+
+	// fromLines[i] and toLines[i] are the line numbers of diffs[i] in each configuration.
+	fromLines := make([]int, len(diffs))
+	toLines := make([]int, len(diffs))
+	fromLine, toLine := 1, 1
+
+	for i, diff := range diffs {
+		fromLines[i], toLines[i] = fromLine, toLine
+
+		if diff.Operation != linediff.Insert {
+			fromLine++
+		}
+
+		if diff.Operation != linediff.Delete {
+			toLine++
+		}
+	}
+
+	end := 0
+
+	for i, diff := range diffs {
+		if diff.Operation == linediff.Equal {
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		if len(hunks) == 0 || start > end {
+			hunks = append(hunks, configDiffHunk{
+				fromStart: fromLines[start],
+				toStart:   toLines[start],
+			})
+		} else {
+			start = end
+		}
+
+		end = i + context + 1
+		if end > len(diffs) {
+			end = len(diffs)
+		}
+
+		hunk := &hunks[len(hunks)-1]
+		hunk.diffs = append(hunk.diffs, diffs[start:end]...)
+	}
+
+	return hunks
+}
+
+func (cd *ConfigDiff) writeUnifiedHunk(output *strings.Builder, hunk configDiffHunk) {
+	fromCount, toCount := 0, 0
+
+	for _, diff := range hunk.diffs {
+		if diff.Operation != linediff.Insert {
+			fromCount++
+		}
+
+		if diff.Operation != linediff.Delete {
+			toCount++
+		}
+	}
+
+	header := fmt.Sprintf(
+		"@@ -%s +%s @@",
+		unifiedRange(hunk.fromStart, fromCount),
+		unifiedRange(hunk.toStart, toCount),
+	)
+	output.WriteString(cd.color(colorCyan, header) + "\n")
+
+	for _, diff := range hunk.diffs {
+		switch diff.Operation {
+		case linediff.Equal:
+			output.WriteString(" " + diff.Line + "\n")
+		case linediff.Delete:
+			output.WriteString(cd.color(colorRed, "-"+diff.Line) + "\n")
+		case linediff.Insert:
+			output.WriteString(cd.color(colorGreen, "+"+diff.Line) + "\n")
+		}
+	}
+}
+
+// unifiedRange follows the diff format: an empty range starts at the line before it.
+func unifiedRange(start int, count int) string {
+	if count == 0 {
+		start--
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// writeSideBySideHunk pairs the deleted lines with the inserted lines which follow them.
+func (cd *ConfigDiff) writeSideBySideHunk(output *strings.Builder, hunk configDiffHunk) {
+	columnWidth := (cd.options.Width - len(" | ")) / 2
+	if columnWidth < minConfigDiffColumnWidth {
+		columnWidth = minConfigDiffColumnWidth
+	}
+
+	header := fmt.Sprintf("@@ line %d | line %d @@", hunk.fromStart, hunk.toStart)
+	output.WriteString(cd.color(colorCyan, header) + "\n")
+
+	for i := 0; i < len(hunk.diffs); {
+		if hunk.diffs[i].Operation == linediff.Equal {
+			line := fitColumn(hunk.diffs[i].Line, columnWidth)
+			output.WriteString(strings.TrimRight(line+"   "+line, " ") + "\n")
+			i++
+
+			continue
+		}
+
+		deleted, inserted := []string{}, []string{}
+
+		for ; i < len(hunk.diffs) && hunk.diffs[i].Operation == linediff.Delete; i++ {
+			deleted = append(deleted, hunk.diffs[i].Line)
+		}
+
+		for ; i < len(hunk.diffs) && hunk.diffs[i].Operation == linediff.Insert; i++ {
+			inserted = append(inserted, hunk.diffs[i].Line)
+		}
+
+		for row := 0; row < len(deleted) || row < len(inserted); row++ {
+			left, right, separator := fitColumn("", columnWidth), "", " | "
+
+			switch {
+			case row >= len(deleted):
+				separator = " > "
+			case row >= len(inserted):
+				separator = " < "
+			}
+
+			if row < len(deleted) {
+				left = cd.color(colorRed, fitColumn(deleted[row], columnWidth))
+			}
+
+			if row < len(inserted) {
+				right = cd.color(colorGreen, strings.TrimRight(fitColumn(inserted[row], columnWidth), " "))
+			}
+
+			output.WriteString(strings.TrimRight(left+separator+right, " ") + "\n")
+		}
+	}
+}
+
+// fitColumn pads or truncates the line to the width of a column.
+func fitColumn(line string, width int) string {
+	length := utf8.RuneCountInString(line)
+
+	if length > width {
+		return string([]rune(line)[:width-1]) + "~"
+	}
+
+	return line + strings.Repeat(" ", width-length)
+}
+
+func (cd *ConfigDiff) color(color string, text string) string {
+	if !cd.options.Color {
+		return text
+	}
+
+	return color + text + colorReset
+}
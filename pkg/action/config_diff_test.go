@@ -0,0 +1,184 @@
+package action_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+)
+
+func getConfigDiffCompareResult() action.CompareResult {
+	return action.CompareResult{
+		AliasName:        "stretchy-products",
+		CurrentIndexName: "stretchy-products-100",
+		CurrentConfig: configuration.New(
+			configuration.Mappings{
+				"properties": map[string]interface{}{
+					"id":    map[string]interface{}{"type": "keyword"},
+					"name":  map[string]interface{}{"type": "text"},
+					"price": map[string]interface{}{"type": "long"},
+				},
+			},
+			configuration.Settings{"number_of_shards": "1"},
+		),
+		NewConfig: configuration.New(
+			configuration.Mappings{
+				"properties": map[string]interface{}{
+					"id":    map[string]interface{}{"type": "keyword"},
+					"name":  map[string]interface{}{"type": "keyword"},
+					"price": map[string]interface{}{"type": "long"},
+				},
+			},
+			configuration.Settings{"number_of_shards": "2"},
+		),
+	}
+}
+
+func TestConfigDiff_Render(t *testing.T) {
+	testCases := []struct {
+		name     string
+		options  action.ConfigDiffOptions
+		expected string
+	}{
+		{
+			name:    "unified yaml",
+			options: action.ConfigDiffOptions{Context: 1},
+			expected: "--- stretchy-products-100 (current)\n" +
+				"+++ stretchy-products (new)\n" +
+				"@@ -5,3 +5,3 @@\n" +
+				"     name:\n" +
+				"-      type: text\n" +
+				"+      type: keyword\n" +
+				"     price:\n" +
+				"@@ -10,2 +10,2 @@\n" +
+				"   index:\n" +
+				"-    number_of_shards: \"1\"\n" +
+				"+    number_of_shards: \"2\"\n",
+		},
+		{
+			name:    "unified json with a context merging the changes",
+			options: action.ConfigDiffOptions{Syntax: action.ConfigDiffJSON, Context: 4},
+			expected: "--- stretchy-products-100 (current)\n" +
+				"+++ stretchy-products (new)\n" +
+				"@@ -4,17 +4,17 @@\n" +
+				"       \"id\": {\n" +
+				"         \"type\": \"keyword\"\n" +
+				"       },\n" +
+				"       \"name\": {\n" +
+				"-        \"type\": \"text\"\n" +
+				"+        \"type\": \"keyword\"\n" +
+				"       },\n" +
+				"       \"price\": {\n" +
+				"         \"type\": \"long\"\n" +
+				"       }\n" +
+				"     }\n" +
+				"   },\n" +
+				"   \"settings\": {\n" +
+				"     \"index\": {\n" +
+				"-      \"number_of_shards\": \"1\"\n" +
+				"+      \"number_of_shards\": \"2\"\n" +
+				"     }\n" +
+				"   }\n" +
+				" }\n",
+		},
+		{
+			name:    "side by side",
+			options: action.ConfigDiffOptions{Layout: action.ConfigDiffSideBySide, Context: 0, Width: 63},
+			expected: "--- stretchy-products-100 (current)\n" +
+				"+++ stretchy-products (new)\n" +
+				"@@ line 6 | line 6 @@\n" +
+				"      type: text               |       type: keyword\n" +
+				"@@ line 11 | line 11 @@\n" +
+				"    number_of_shards: \"1\"      |     number_of_shards: \"2\"\n",
+		},
+		{
+			name:    "color",
+			options: action.ConfigDiffOptions{Context: 0, Color: true},
+			expected: "\x1b[1m--- stretchy-products-100 (current)\x1b[0m\n" +
+				"\x1b[1m+++ stretchy-products (new)\x1b[0m\n" +
+				"\x1b[36m@@ -6,1 +6,1 @@\x1b[0m\n" +
+				"\x1b[31m-      type: text\x1b[0m\n" +
+				"\x1b[32m+      type: keyword\x1b[0m\n" +
+				"\x1b[36m@@ -11,1 +11,1 @@\x1b[0m\n" +
+				"\x1b[31m-    number_of_shards: \"1\"\x1b[0m\n" +
+				"\x1b[32m+    number_of_shards: \"2\"\x1b[0m\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := action.NewConfigDiff(tc.options).Render(getConfigDiffCompareResult())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, output)
+		})
+	}
+}
+
+func TestConfigDiff_Render_Create(t *testing.T) {
+	compareResult := getConfigDiffCompareResult()
+	compareResult.CurrentIndexName = ""
+	compareResult.CurrentConfig = configuration.Index{}
+	compareResult.NewConfig = configuration.New(
+		configuration.Mappings{"properties": map[string]interface{}{}},
+		configuration.Settings{},
+	)
+
+	output, err := action.NewConfigDiff(action.ConfigDiffOptions{Context: 3}).Render(compareResult)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"--- /dev/null (current)\n"+
+			"+++ stretchy-products (new)\n"+
+			"@@ -0,0 +1,3 @@\n"+
+			"+mappings:\n"+
+			"+  properties: {}\n"+
+			"+settings: {}\n",
+		output,
+	)
+}
+
+func TestConfigDiff_Render_NoChange(t *testing.T) {
+	compareResult := getConfigDiffCompareResult()
+	compareResult.NewConfig = compareResult.CurrentConfig
+
+	output, err := action.NewConfigDiff(action.ConfigDiffOptions{}).Render(compareResult)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", output)
+}
+
+func TestConfigDiff_Render_UnreportedChange(t *testing.T) {
+	compareResult := getConfigDiffCompareResult()
+	compareResult.CurrentConfig.Mappings["properties"].(map[string]interface{})["name"] = map[string]interface{}{
+		"type":         "keyword",
+		"ignore_above": float64(256),
+	}
+
+	output, err := action.NewConfigDiff(action.ConfigDiffOptions{Context: 1}).Render(compareResult)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"--- stretchy-products-100 (current)\n"+
+			"+++ stretchy-products (new)\n"+
+			"@@ -11,2 +11,2 @@\n"+
+			"   index:\n"+
+			"-    number_of_shards: \"1\"\n"+
+			"+    number_of_shards: \"2\"\n",
+		output,
+	)
+}
+
+func TestNewConfigDiffLayoutFromString(t *testing.T) {
+	layout, err := action.NewConfigDiffLayoutFromString("side-by-side")
+	assert.NoError(t, err)
+	assert.Equal(t, action.ConfigDiffSideBySide, layout)
+
+	_, err = action.NewConfigDiffLayoutFromString("split")
+	assert.EqualError(t, err, "unknown diff layout 'split'")
+}
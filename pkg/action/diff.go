@@ -0,0 +1,93 @@
+package action
+
+import (
+	"sort"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+// DiffResult compares two versions of a configuration, without any cluster.
+type DiffResult struct {
+	ConfigurationName string
+	// Removed is set when the configuration only exists in the from version, apply leaves its index untouched.
+	Removed bool
+	// Result is what apply would do on an index having the from version of the configuration.
+	Result strategy.IndexVoterResult
+}
+
+type DiffResultCollection []DiffResult
+
+type Diff struct {
+	indexActionVoter *strategy.IndexActionVoter
+}
+
+func NewDiff(updateEnabled bool) *Diff {
+	return &Diff{
+		indexActionVoter: strategy.NewIndexActionVoter(updateEnabled),
+	}
+}
+
+// DiffAll compares the configurations of both collections by name, the results are sorted by name.
+func (d *Diff) DiffAll(
+	from configuration.IndexCollection,
+	to configuration.IndexCollection,
+) (DiffResultCollection, error) {
+	names := []string{}
+
+	for name := range from {
+		names = append(names, name)
+	}
+
+	for name := range to {
+		if !from.Exist(name) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	diffResultCollection := DiffResultCollection{}
+
+	for _, name := range names {
+		diffResult, err := d.diff(name, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		diffResultCollection = append(diffResultCollection, diffResult)
+	}
+
+	return diffResultCollection, nil
+}
+
+func (d *Diff) diff(
+	name string,
+	from configuration.IndexCollection,
+	to configuration.IndexCollection,
+) (DiffResult, error) {
+	toIndex, toExist := to[name]
+	if !toExist {
+		return DiffResult{
+			ConfigurationName: name,
+			Removed:           true,
+			Result:            strategy.NewIndexVoterResult(strategy.IndexDecisionNone, nil),
+		}, nil
+	}
+
+	var fromIndex *configuration.Index
+
+	if index, fromExist := from[name]; fromExist {
+		fromIndex = &index
+	}
+
+	result, err := d.indexActionVoter.Compare(fromIndex, &toIndex)
+	if err != nil {
+		return DiffResult{}, err
+	}
+
+	return DiffResult{
+		ConfigurationName: name,
+		Result:            result,
+	}, nil
+}
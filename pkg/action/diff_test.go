@@ -0,0 +1,79 @@
+package action_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+func TestDiff_DiffAll(t *testing.T) {
+	migratedConfiguration := getConfiguration1()
+	migratedConfiguration.Settings["index"].(map[string]interface{})["number_of_shards"] = "3"
+
+	from := configuration.IndexCollection{
+		"unchanged": getConfiguration1(),
+		"updated":   getConfiguration1(),
+		"migrated":  getConfiguration1(),
+		"removed":   getConfiguration1(),
+	}
+
+	to := configuration.IndexCollection{
+		"unchanged": getConfiguration1(),
+		"updated":   getConfiguration2(),
+		"migrated":  migratedConfiguration,
+		"added":     getConfiguration1(),
+	}
+
+	diffResultCollection, err := action.NewDiff(true).DiffAll(from, to)
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		action.DiffResultCollection{
+			{
+				ConfigurationName: "added",
+				Result:            strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
+			},
+			{
+				ConfigurationName: "migrated",
+				Result: strategy.NewIndexVoterResult(
+					strategy.IndexDecisionMigrate,
+					configuration.ChangeCollection{
+						{
+							Type: configuration.ChangeTypeUpdate,
+							Path: []string{"settings", "index", "number_of_shards"},
+							From: "5",
+							To:   "3",
+						},
+					},
+				),
+			},
+			{
+				ConfigurationName: "removed",
+				Removed:           true,
+				Result:            strategy.NewIndexVoterResult(strategy.IndexDecisionNone, nil),
+			},
+			{
+				ConfigurationName: "unchanged",
+				Result:            strategy.NewIndexVoterResult(strategy.IndexDecisionNone, nil),
+			},
+			{
+				ConfigurationName: "updated",
+				Result: strategy.NewIndexVoterResult(
+					strategy.IndexDecisionUpdate,
+					configuration.ChangeCollection{
+						{
+							Type: configuration.ChangeTypeCreate,
+							Path: []string{"mappings", "properties", "updated_at"},
+							To:   map[string]interface{}{"type": "date"},
+						},
+					},
+				),
+			},
+		},
+		diffResultCollection,
+	)
+}
@@ -0,0 +1,369 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+const DefaultHistoryIndexName = ".stretchy-history"
+const DefaultHistoryLimit = 20
+
+type HistoryOutcome int
+
+const (
+	HistoryOutcomeSuccess HistoryOutcome = iota
+	HistoryOutcomeFailure
+)
+
+func (ho HistoryOutcome) String() string {
+	return historyOutcomeNames()[ho]
+}
+
+func historyOutcomeNames() []string {
+	return []string{"success", "failure"}
+}
+
+func NewHistoryOutcomeFromString(outcome string) (HistoryOutcome, error) {
+	for i, name := range historyOutcomeNames() {
+		if name == outcome {
+			return HistoryOutcome(i), nil
+		}
+	}
+
+	return HistoryOutcomeSuccess, fmt.Errorf("unknown history outcome '%s'", outcome)
+}
+
+func (ho HistoryOutcome) MarshalText() ([]byte, error) {
+	return []byte(ho.String()), nil
+}
+
+func (ho *HistoryOutcome) UnmarshalText(text []byte) error {
+	outcome, err := NewHistoryOutcomeFromString(string(text))
+	if err != nil {
+		return err
+	}
+
+	*ho = outcome
+
+	return nil
+}
+
+// HistoryReindexStats sums up the reindex tasks of a migration.
+type HistoryReindexStats struct {
+	Tasks            int   `json:"tasks" yaml:"tasks"`
+	Total            int64 `json:"total" yaml:"total"`
+	Created          int64 `json:"created" yaml:"created"`
+	Updated          int64 `json:"updated" yaml:"updated"`
+	VersionConflicts int64 `json:"version_conflicts" yaml:"version_conflicts"`
+	Failures         int   `json:"failures" yaml:"failures"`
+	DurationMillis   int64 `json:"duration_ms" yaml:"duration_ms"`
+}
+
+func newHistoryReindexStats(tasks []elasticsearch.ReindexTask) *HistoryReindexStats {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	stats := &HistoryReindexStats{}
+
+	for _, task := range tasks {
+		stats.Tasks++
+		stats.Total += task.Status.Total
+		stats.Created += task.Status.Created
+		stats.Updated += task.Status.Updated
+		stats.VersionConflicts += task.Status.VersionConflicts
+		stats.Failures += len(task.Failures)
+		stats.DurationMillis += int64(task.RunningTime / time.Millisecond)
+	}
+
+	return stats
+}
+
+// HistoryRecord is the document recorded in the history index for each applied compare result.
+type HistoryRecord struct {
+	AliasName         string                         `json:"alias_name"`
+	ConfigurationName string                         `json:"configuration_name"`
+	Action            strategy.IndexAction           `json:"action"`
+	PreviousIndexName string                         `json:"previous_index_name"`
+	IndexName         string                         `json:"index_name"`
+	Changes           configuration.ChangeCollection `json:"changes"`
+	StartedAt         time.Time                      `json:"started_at"`
+	DurationMillis    int64                          `json:"duration_ms"`
+	Reindex           *HistoryReindexStats           `json:"reindex,omitempty"`
+	Outcome           HistoryOutcome                 `json:"outcome"`
+	Error             string                         `json:"error,omitempty"`
+	User              string                         `json:"user"`
+	Host              string                         `json:"host"`
+}
+
+// HistoryOptions is a set of flags to configure a History.
+type HistoryOptions struct {
+	IndexName string
+	// User and Host identify who applied the changes, the current user and host name by default.
+	User string
+	Host string
+}
+
+// HistoryFilter selects the history records, its zero value selects all of them.
+type HistoryFilter struct {
+	AliasName string
+	Action    *strategy.IndexAction
+	Outcome   *HistoryOutcome
+	Since     time.Time
+	Limit     int
+}
+
+// History records what has been applied on the cluster in a dedicated index.
+type History struct {
+	client  elasticsearch.Client
+	options HistoryOptions
+}
+
+func NewHistory(
+	client elasticsearch.Client,
+	options HistoryOptions,
+) *History {
+	if options.IndexName == "" {
+		options.IndexName = DefaultHistoryIndexName
+	}
+
+	if options.User == "" {
+		options.User = defaultHistoryUser()
+	}
+
+	if options.Host == "" {
+		options.Host, _ = os.Hostname()
+	}
+
+	return &History{
+		client:  client,
+		options: options,
+	}
+}
+
+func defaultHistoryUser() string {
+	currentUser, err := user.Current()
+	if err != nil {
+		return os.Getenv("USER")
+	}
+
+	return currentUser.Username
+}
+
+// NewHistoryRecord describes an apply outcome.
+func (h *History) NewHistoryRecord(outcome ApplyOutcome) HistoryRecord {
+	compareResult := outcome.CompareResult
+
+	record := HistoryRecord{
+		AliasName:         compareResult.AliasName,
+		ConfigurationName: compareResult.ConfigurationName,
+		Action:            compareResult.Result.Action(),
+		PreviousIndexName: compareResult.CurrentIndexName,
+		IndexName:         outcome.IndexName,
+		Changes:           compareResult.Result.Changes(),
+		StartedAt:         outcome.StartedAt,
+		DurationMillis:    int64(outcome.Duration / time.Millisecond),
+		Reindex:           newHistoryReindexStats(outcome.ReindexTasks),
+		Outcome:           HistoryOutcomeSuccess,
+		User:              h.options.User,
+		Host:              h.options.Host,
+	}
+
+	if outcome.Err != nil {
+		record.Outcome = HistoryOutcomeFailure
+		record.Error = outcome.Err.Error()
+	}
+
+	return record
+}
+
+// Record stores the outcome of an apply, nothing is recorded when there was nothing to apply.
+func (h *History) Record(outcome ApplyOutcome) error {
+	if outcome.CompareResult.Result.Action() == strategy.IndexDecisionNone {
+		return nil
+	}
+
+	return h.create(h.NewHistoryRecord(outcome))
+}
+
+// NewRollbackHistoryRecord describes a rollback outcome.
+func (h *History) NewRollbackHistoryRecord(outcome RollbackOutcome) HistoryRecord {
+	rollbackResult := outcome.RollbackResult
+
+	record := HistoryRecord{
+		AliasName:         rollbackResult.AliasName,
+		Action:            strategy.IndexDecisionRollback,
+		PreviousIndexName: rollbackResult.CurrentIndexName,
+		IndexName:         rollbackResult.TargetIndexName,
+		Changes:           rollbackResult.Changes,
+		StartedAt:         outcome.StartedAt,
+		DurationMillis:    int64(outcome.Duration / time.Millisecond),
+		Outcome:           HistoryOutcomeSuccess,
+		User:              h.options.User,
+		Host:              h.options.Host,
+	}
+
+	if outcome.Err != nil {
+		record.Outcome = HistoryOutcomeFailure
+		record.Error = outcome.Err.Error()
+	}
+
+	return record
+}
+
+// RecordRollback stores the outcome of a rollback.
+func (h *History) RecordRollback(outcome RollbackOutcome) error {
+	return h.create(h.NewRollbackHistoryRecord(outcome))
+}
+
+func (h *History) create(record HistoryRecord) error {
+	if err := elasticsearch.EnsureIndex(h.client, h.options.IndexName, historyIndexConfiguration()); err != nil {
+		return err
+	}
+
+	id := fmt.Sprintf("%s-%d", record.AliasName, record.StartedAt.UnixNano())
+
+	_, err := h.client.CreateDocument(h.options.IndexName, id, record)
+
+	return err
+}
+
+func (h *History) RecordAll(outcomes ApplyOutcomeCollection) error {
+	for _, outcome := range outcomes {
+		if err := h.Record(outcome); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// List returns the records matching the filter, from the newest to the oldest.
+func (h *History) List(filter HistoryFilter) ([]HistoryRecord, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+
+	sources, err := h.client.SearchDocuments(h.options.IndexName, map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": historyFilterClauses(filter),
+			},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{"started_at": "desc"},
+		},
+		"size": limit,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]HistoryRecord, 0, len(sources))
+
+	for _, source := range sources {
+		record := HistoryRecord{}
+		if err := json.Unmarshal(source, &record); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func historyFilterClauses(filter HistoryFilter) []interface{} {
+	clauses := []interface{}{}
+
+	if filter.AliasName != "" {
+		clauses = append(clauses, historyTermClause("alias_name", filter.AliasName))
+	}
+
+	if filter.Action != nil {
+		clauses = append(clauses, historyTermClause("action", filter.Action.String()))
+	}
+
+	if filter.Outcome != nil {
+		clauses = append(clauses, historyTermClause("outcome", filter.Outcome.String()))
+	}
+
+	if !filter.Since.IsZero() {
+		clauses = append(clauses, map[string]interface{}{
+			"range": map[string]interface{}{
+				"started_at": map[string]interface{}{"gte": filter.Since.Format(time.RFC3339)},
+			},
+		})
+	}
+
+	return clauses
+}
+
+func historyTermClause(field string, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"term": map[string]interface{}{field: value},
+	}
+}
+
+// LastMigration returns the last successful migration which created the index, nil when there is none.
+func (h *History) LastMigration(aliasName string, indexName string) (*HistoryRecord, error) {
+	migrate := strategy.IndexDecisionMigrate
+	outcome := HistoryOutcomeSuccess
+
+	records, err := h.List(HistoryFilter{
+		AliasName: aliasName,
+		Action:    &migrate,
+		Outcome:   &outcome,
+		Limit:     100,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if record.IndexName == indexName {
+			record := record
+
+			return &record, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// historyIndexConfiguration doesn't index the changes, their values have any type.
+func historyIndexConfiguration() configuration.Index {
+	return configuration.New(
+		configuration.Mappings{
+			"dynamic": false,
+			"properties": map[string]interface{}{
+				"alias_name":          map[string]interface{}{"type": "keyword"},
+				"configuration_name":  map[string]interface{}{"type": "keyword"},
+				"action":              map[string]interface{}{"type": "keyword"},
+				"previous_index_name": map[string]interface{}{"type": "keyword"},
+				"index_name":          map[string]interface{}{"type": "keyword"},
+				"changes":             map[string]interface{}{"type": "object", "enabled": false},
+				"started_at":          map[string]interface{}{"type": "date"},
+				"duration_ms":         map[string]interface{}{"type": "long"},
+				"outcome":             map[string]interface{}{"type": "keyword"},
+				"error":               map[string]interface{}{"type": "text"},
+				"user":                map[string]interface{}{"type": "keyword"},
+				"host":                map[string]interface{}{"type": "keyword"},
+			},
+		},
+		configuration.Settings{
+			"number_of_shards":     "1",
+			"auto_expand_replicas": "0-1",
+		},
+	)
+}
@@ -0,0 +1,219 @@
+package action_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+const historyIndexName = ".stretchy-history"
+
+func getHistoryOptions() action.HistoryOptions {
+	return action.HistoryOptions{
+		IndexName: historyIndexName,
+		User:      "jane",
+		Host:      "ci-runner",
+	}
+}
+
+func getMigrateOutcome() action.ApplyOutcome {
+	return action.ApplyOutcome{
+		CompareResult: action.CompareResult{
+			ConfigurationName: "products",
+			AliasName:         "stretchy-products",
+			CurrentIndexName:  "stretchy-products-100",
+			Result: strategy.NewIndexVoterResult(
+				strategy.IndexDecisionMigrate,
+				configuration.ChangeCollection{
+					{
+						Type: configuration.ChangeTypeUpdate,
+						Path: []string{"mappings", "properties", "name", "type"},
+						From: "text",
+						To:   "keyword",
+					},
+				},
+			),
+		},
+		IndexName: "stretchy-products-100",
+		StartedAt: time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC),
+		Duration:  90 * time.Second,
+		ReindexTasks: []elasticsearch.ReindexTask{
+			{
+				ID:          "node:1",
+				Completed:   true,
+				Status:      elasticsearch.ReindexStatus{Total: 10, Created: 8},
+				RunningTime: time.Minute,
+				Failures:    []elasticsearch.ReindexFailure{{Index: "stretchy-products-200", ID: "1"}},
+			},
+			{
+				ID:          "node:2",
+				Completed:   true,
+				Status:      elasticsearch.ReindexStatus{Total: 2, Updated: 2},
+				RunningTime: time.Second,
+			},
+		},
+		Err: errors.New("reindex failed"),
+	}
+}
+
+func TestHistory_NewHistoryRecord(t *testing.T) {
+	history := action.NewHistory(elasticsearch.NewMockClient(), getHistoryOptions())
+
+	record := history.NewHistoryRecord(getMigrateOutcome())
+
+	assert.Equal(
+		t,
+		action.HistoryRecord{
+			AliasName:         "stretchy-products",
+			ConfigurationName: "products",
+			Action:            strategy.IndexDecisionMigrate,
+			PreviousIndexName: "stretchy-products-100",
+			IndexName:         "stretchy-products-100",
+			Changes:           getMigrateOutcome().CompareResult.Result.Changes(),
+			StartedAt:         time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC),
+			DurationMillis:    90000,
+			Reindex: &action.HistoryReindexStats{
+				Tasks:          2,
+				Total:          12,
+				Created:        8,
+				Updated:        2,
+				Failures:       1,
+				DurationMillis: 61000,
+			},
+			Outcome: action.HistoryOutcomeFailure,
+			Error:   "reindex failed",
+			User:    "jane",
+			Host:    "ci-runner",
+		},
+		record,
+	)
+
+	encodedRecord, err := json.Marshal(record)
+	assert.NoError(t, err)
+	assert.Contains(t, string(encodedRecord), `"action":"Migrate"`)
+	assert.Contains(t, string(encodedRecord), `"outcome":"failure"`)
+}
+
+func TestHistory_RecordAll(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	client.On("IndexExist", historyIndexName).Return(true, nil)
+	client.On("CreateDocument", historyIndexName, "stretchy-products-1589533200000000000", mock.Anything).
+		Return(elasticsearch.DocumentVersion{}, nil).
+		Run(func(args mock.Arguments) {
+			record := args.Get(2).(action.HistoryRecord)
+			assert.Equal(t, action.HistoryOutcomeFailure, record.Outcome)
+		})
+
+	history := action.NewHistory(client, getHistoryOptions())
+
+	err := history.RecordAll(action.ApplyOutcomeCollection{
+		{
+			CompareResult: action.CompareResult{
+				AliasName: "stretchy-other",
+				Result:    strategy.NewIndexVoterResult(strategy.IndexDecisionNone, nil),
+			},
+		},
+		getMigrateOutcome(),
+	})
+
+	assert.NoError(t, err)
+
+	mock.AssertExpectationsForObjects(t, client)
+	client.AssertNumberOfCalls(t, "CreateDocument", 1)
+}
+
+func TestHistory_RecordRollback(t *testing.T) {
+	startedAt := time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	client := elasticsearch.NewMockClient()
+	client.On("IndexExist", historyIndexName).Return(true, nil)
+	client.On("CreateDocument", historyIndexName, "stretchy-products-1589533200000000000", mock.Anything).
+		Return(elasticsearch.DocumentVersion{}, nil).
+		Run(func(args mock.Arguments) {
+			assert.Equal(
+				t,
+				action.HistoryRecord{
+					AliasName:         "stretchy-products",
+					Action:            strategy.IndexDecisionRollback,
+					PreviousIndexName: "stretchy-products-200",
+					IndexName:         "stretchy-products-100",
+					StartedAt:         startedAt,
+					DurationMillis:    1500,
+					Outcome:           action.HistoryOutcomeFailure,
+					Error:             "alias update failed",
+					User:              "jane",
+					Host:              "ci-runner",
+				},
+				args.Get(2).(action.HistoryRecord),
+			)
+		})
+
+	err := action.NewHistory(client, getHistoryOptions()).RecordRollback(action.RollbackOutcome{
+		RollbackResult: action.RollbackResult{
+			AliasName:        "stretchy-products",
+			CurrentIndexName: "stretchy-products-200",
+			TargetIndexName:  "stretchy-products-100",
+		},
+		StartedAt: startedAt,
+		Duration:  1500 * time.Millisecond,
+		Err:       errors.New("alias update failed"),
+	})
+
+	assert.NoError(t, err)
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestHistory_List(t *testing.T) {
+	migrate := strategy.IndexDecisionMigrate
+	failure := action.HistoryOutcomeFailure
+
+	encodedRecord, err := json.Marshal(
+		action.NewHistory(elasticsearch.NewMockClient(), getHistoryOptions()).NewHistoryRecord(getMigrateOutcome()),
+	)
+	assert.NoError(t, err)
+
+	client := elasticsearch.NewMockClient()
+	client.On("SearchDocuments", historyIndexName, map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"term": map[string]interface{}{"alias_name": "stretchy-products"}},
+					map[string]interface{}{"term": map[string]interface{}{"action": "Migrate"}},
+					map[string]interface{}{"term": map[string]interface{}{"outcome": "failure"}},
+					map[string]interface{}{
+						"range": map[string]interface{}{
+							"started_at": map[string]interface{}{"gte": "2020-05-14T09:00:00Z"},
+						},
+					},
+				},
+			},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{"started_at": "desc"},
+		},
+		"size": 5,
+	}).Return([]json.RawMessage{encodedRecord}, nil)
+
+	records, err := action.NewHistory(client, getHistoryOptions()).List(action.HistoryFilter{
+		AliasName: "stretchy-products",
+		Action:    &migrate,
+		Outcome:   &failure,
+		Since:     time.Date(2020, 5, 14, 9, 0, 0, 0, time.UTC),
+		Limit:     5,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "stretchy-products", records[0].AliasName)
+	assert.Equal(t, action.HistoryOutcomeFailure, records[0].Outcome)
+	assert.Equal(t, int64(12), records[0].Reindex.Total)
+	assert.Len(t, records[0].Changes, 1)
+}
@@ -0,0 +1,162 @@
+package action
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/configuration/loader"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+// ImportResult is the configuration of a live index to write as a configuration file.
+type ImportResult struct {
+	ConfigurationName string
+	AliasName         string
+	IndexName         string
+	Config            configuration.Index
+}
+
+type ImportResultCollection []ImportResult
+
+type Import struct {
+	client      elasticsearch.Client
+	indexPrefix string
+}
+
+func NewImport(
+	client elasticsearch.Client,
+	indexPrefix string,
+) *Import {
+	return &Import{
+		client:      client,
+		indexPrefix: indexPrefix,
+	}
+}
+
+// Resolve fetches the configuration of the indices matching the names, each one can be an alias,
+// an index or a pattern. Indices must be behind an alias, since stretchy manages them through it.
+func (i *Import) Resolve(names []string) (ImportResultCollection, error) {
+	aliases := map[string]string{}
+
+	for _, name := range names {
+		nameAliases, err := i.resolveAliases(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for aliasName, indexName := range nameAliases {
+			aliases[aliasName] = indexName
+		}
+	}
+
+	aliasNames := make([]string, 0, len(aliases))
+	for aliasName := range aliases {
+		aliasNames = append(aliasNames, aliasName)
+	}
+
+	sort.Strings(aliasNames)
+
+	importResultCollection := ImportResultCollection{}
+
+	for _, aliasName := range aliasNames {
+		configurationName, err := i.configurationName(aliasName)
+		if err != nil {
+			return nil, err
+		}
+
+		config, err := i.client.GetIndexConfiguration(aliases[aliasName])
+		if err != nil {
+			return nil, err
+		}
+
+		// The provenance metadata is written back by the next apply.
+		config, _ = config.ExtractMetadata()
+
+		importResultCollection = append(importResultCollection, ImportResult{
+			ConfigurationName: configurationName,
+			AliasName:         aliasName,
+			IndexName:         aliases[aliasName],
+			Config:            config,
+		})
+	}
+
+	return importResultCollection, nil
+}
+
+// Save writes the configurations in the format read by the loaders, existing files are never overwritten.
+func (i *Import) Save(importResultCollection ImportResultCollection, basePath string, format string) error {
+	l, err := loader.NewRegistry(basePath).GetByFormat(format)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(basePath, 0755); err != nil {
+		return err
+	}
+
+	for _, importResult := range importResultCollection {
+		if err := l.Save(importResult.ConfigurationName, importResult.Config); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *Import) resolveAliases(name string) (map[string]string, error) {
+	aliases, err := i.client.ListAliases(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(aliases) > 0 {
+		return aliases, nil
+	}
+
+	indices, err := i.client.ListIndices(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("no alias or index matches '%s'", name)
+	}
+
+	indexAliases := map[string]string{}
+
+	// Only the aliases of the matched indices are read, an unrelated alias may target several indices.
+	for _, index := range indices {
+		aliasNames, err := i.client.GetIndexAliases(index.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch len(aliasNames) {
+		case 0:
+			return nil, fmt.Errorf("index '%s' is not targeted by an alias, create one to manage it with stretchy", index.Name)
+		case 1:
+			indexAliases[aliasNames[0]] = index.Name
+		default:
+			sort.Strings(aliasNames)
+
+			return nil, fmt.Errorf("index '%s' is targeted by several aliases, import one of %v", index.Name, aliasNames)
+		}
+	}
+
+	return indexAliases, nil
+}
+
+func (i *Import) configurationName(aliasName string) (string, error) {
+	if i.indexPrefix == "" {
+		return aliasName, nil
+	}
+
+	if !strings.HasPrefix(aliasName, i.indexPrefix+"-") {
+		return "", fmt.Errorf("alias '%s' doesn't start with the index prefix '%s'", aliasName, i.indexPrefix)
+	}
+
+	return strings.TrimPrefix(aliasName, i.indexPrefix+"-"), nil
+}
@@ -0,0 +1,161 @@
+package action_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+func importedConfig() configuration.Index {
+	return configuration.New(
+		configuration.Mappings{
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":         "keyword",
+					"ignore_above": float64(256),
+				},
+			},
+		},
+		configuration.Settings{
+			"index": map[string]interface{}{
+				"number_of_shards": "1",
+			},
+		},
+	)
+}
+
+func TestImport_Resolve(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	client.On("ListAliases", "stretchy-*").Return(
+		map[string]string{
+			"stretchy-products": "stretchy-products-100",
+			"stretchy-authors":  "stretchy-authors-100",
+		},
+		nil,
+	)
+	client.On("ListAliases", "legacy-index").Return(map[string]string{}, nil)
+	client.On("ListIndices", "legacy-index").Return([]elasticsearch.IndexInfo{{Name: "legacy-index"}}, nil)
+	client.On("GetIndexAliases", "legacy-index").Return([]string{"stretchy-legacy"}, nil)
+	client.On("GetIndexConfiguration", "stretchy-products-100").Return(importedConfig(), nil)
+	client.On("GetIndexConfiguration", "stretchy-authors-100").Return(importedConfig(), nil)
+	client.On("GetIndexConfiguration", "legacy-index").Return(importedConfig(), nil)
+
+	importResultCollection, err := action.NewImport(client, "stretchy").Resolve([]string{"stretchy-*", "legacy-index"})
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		action.ImportResultCollection{
+			{
+				ConfigurationName: "authors",
+				AliasName:         "stretchy-authors",
+				IndexName:         "stretchy-authors-100",
+				Config:            importedConfig(),
+			},
+			{
+				ConfigurationName: "legacy",
+				AliasName:         "stretchy-legacy",
+				IndexName:         "legacy-index",
+				Config:            importedConfig(),
+			},
+			{
+				ConfigurationName: "products",
+				AliasName:         "stretchy-products",
+				IndexName:         "stretchy-products-100",
+				Config:            importedConfig(),
+			},
+		},
+		importResultCollection,
+	)
+}
+
+func TestImport_Resolve_Errors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		indexPrefix string
+		setup       func(client *elasticsearch.MockClient)
+	}{
+		{
+			name: "nothing matches",
+			setup: func(client *elasticsearch.MockClient) {
+				client.On("ListAliases", "products").Return(map[string]string{}, nil)
+				client.On("ListIndices", "products").Return([]elasticsearch.IndexInfo{}, nil)
+			},
+		},
+		{
+			name: "index without alias",
+			setup: func(client *elasticsearch.MockClient) {
+				client.On("ListAliases", "products").Return(map[string]string{}, nil)
+				client.On("ListIndices", "products").Return([]elasticsearch.IndexInfo{{Name: "products"}}, nil)
+				client.On("GetIndexAliases", "products").Return([]string{}, nil)
+			},
+		},
+		{
+			name: "index with several aliases",
+			setup: func(client *elasticsearch.MockClient) {
+				client.On("ListAliases", "products").Return(map[string]string{}, nil)
+				client.On("ListIndices", "products").Return([]elasticsearch.IndexInfo{{Name: "products"}}, nil)
+				client.On("GetIndexAliases", "products").Return([]string{"products-b", "products-a"}, nil)
+			},
+		},
+		{
+			name:        "alias without the prefix",
+			indexPrefix: "stretchy",
+			setup: func(client *elasticsearch.MockClient) {
+				client.On("ListAliases", "products").Return(map[string]string{"products": "products-100"}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := elasticsearch.NewMockClient()
+			tc.setup(client)
+
+			_, err := action.NewImport(client, tc.indexPrefix).Resolve([]string{"products"})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestImport_Save(t *testing.T) {
+	for _, format := range []string{"yaml", "json"} {
+		format := format
+		t.Run(format, func(t *testing.T) {
+			basePath := t.TempDir()
+
+			client := elasticsearch.NewMockClient()
+			client.On("AliasExist", "stretchy-products").Return(true, nil)
+			client.On("GetAliasedIndex", "stretchy-products").Return("stretchy-products-100", nil)
+			client.On("GetIndexConfiguration", "stretchy-products-100").Return(importedConfig(), nil)
+
+			err := action.NewImport(client, "stretchy").Save(
+				action.ImportResultCollection{
+					{
+						ConfigurationName: "products",
+						AliasName:         "stretchy-products",
+						IndexName:         "stretchy-products-100",
+						Config:            importedConfig(),
+					},
+				},
+				basePath,
+				format,
+			)
+			assert.NoError(t, err)
+
+			indexCollection, err := action.NewLoad(basePath).LoadAll(format)
+			assert.NoError(t, err)
+
+			// Applying an imported configuration must not change anything.
+			compareResultCollection, err := action.NewCompare(client, "stretchy", true).CompareAll(indexCollection)
+			assert.NoError(t, err)
+			assert.Len(t, compareResultCollection, 1)
+			assert.Equal(t, strategy.IndexDecisionNone, compareResultCollection[0].Result.Action())
+		})
+	}
+}
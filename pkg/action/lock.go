@@ -0,0 +1,306 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+const DefaultLockIndexName = ".stretchy-lock"
+const DefaultLockTTL = 5 * time.Minute
+
+// lockDocumentID is the id of the lock document, there is a single lock per cluster.
+const lockDocumentID = "apply"
+
+// LockOptions is a set of flags to configure a Lock.
+type LockOptions struct {
+	IndexName string
+	// Owner identifies the holder of the lock, the host name and the process id by default.
+	Owner string
+	// TTL is how long the lock is held without heartbeat, so that a crashed job doesn't keep it forever.
+	TTL time.Duration
+	// HeartbeatInterval is how often the lock is extended while it is held, a third of the TTL by default.
+	HeartbeatInterval time.Duration
+}
+
+// LockInfo is the lock document stored in the lock index.
+type LockInfo struct {
+	Owner       string    `json:"owner"`
+	AcquiredAt  time.Time `json:"acquired_at"`
+	HeartbeatAt time.Time `json:"heartbeat_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// IsExpired tells if the holder of the lock stopped extending it.
+func (li LockInfo) IsExpired() bool {
+	return time.Now().After(li.ExpiresAt)
+}
+
+// Lock is a lock stored in the cluster, it prevents concurrent applies from migrating the same indices.
+type Lock struct {
+	client  elasticsearch.Client
+	options LockOptions
+
+	mutex   sync.Mutex
+	info    LockInfo
+	version elasticsearch.DocumentVersion
+	lostErr error
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func NewLock(
+	client elasticsearch.Client,
+	options LockOptions,
+) *Lock {
+	if options.IndexName == "" {
+		options.IndexName = DefaultLockIndexName
+	}
+
+	if options.Owner == "" {
+		options.Owner = defaultLockOwner()
+	}
+
+	if options.TTL <= 0 {
+		options.TTL = DefaultLockTTL
+	}
+
+	if options.HeartbeatInterval <= 0 {
+		options.HeartbeatInterval = options.TTL / 3
+	}
+
+	return &Lock{
+		client:  client,
+		options: options,
+	}
+}
+
+func defaultLockOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}
+
+// Acquire takes the lock, an expired lock is taken over. The lock is extended in background until it is released.
+func (l *Lock) Acquire() error {
+	if err := elasticsearch.EnsureIndex(l.client, l.options.IndexName, lockIndexConfiguration()); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	info := LockInfo{
+		Owner:       l.options.Owner,
+		AcquiredAt:  now,
+		HeartbeatAt: now,
+		ExpiresAt:   now.Add(l.options.TTL),
+	}
+
+	version, err := l.client.CreateDocument(l.options.IndexName, lockDocumentID, info)
+	if elasticsearch.IsDocumentConflict(err) {
+		version, err = l.takeOver(info)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	l.info = info
+	l.version = version
+	l.lostErr = nil
+	l.stop = make(chan struct{})
+	l.stopped = make(chan struct{})
+
+	go l.extend()
+
+	return nil
+}
+
+// takeOver replaces the lock of a holder that stopped extending it.
+func (l *Lock) takeOver(info LockInfo) (elasticsearch.DocumentVersion, error) {
+	currentInfo, document, err := l.get()
+	if err != nil {
+		return elasticsearch.DocumentVersion{}, err
+	}
+
+	if currentInfo != nil && !currentInfo.IsExpired() {
+		return elasticsearch.DocumentVersion{}, fmt.Errorf(
+			"cluster is locked by '%s' since %s, the lock expires at %s",
+			currentInfo.Owner,
+			currentInfo.AcquiredAt.Format(time.RFC3339),
+			currentInfo.ExpiresAt.Format(time.RFC3339),
+		)
+	}
+
+	var version elasticsearch.DocumentVersion
+
+	// The lock may have been released in the meantime.
+	if document == nil {
+		version, err = l.client.CreateDocument(l.options.IndexName, lockDocumentID, info)
+	} else {
+		version, err = l.client.UpdateDocument(l.options.IndexName, lockDocumentID, info, document.Version)
+	}
+
+	if elasticsearch.IsDocumentConflict(err) {
+		return version, fmt.Errorf("cluster has been locked concurrently by another job")
+	}
+
+	return version, err
+}
+
+func (l *Lock) extend() {
+	defer close(l.stopped)
+
+	ticker := time.NewTicker(l.options.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			// A failed heartbeat is tried again on the next tick, unless the lock is lost.
+			if err := l.Heartbeat(); err != nil && l.isLost() {
+				return
+			}
+		}
+	}
+}
+
+// Heartbeat extends the lock, it is called periodically while the lock is held.
+func (l *Lock) Heartbeat() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.lostErr != nil {
+		return l.lostErr
+	}
+
+	now := time.Now()
+	info := l.info
+	info.HeartbeatAt = now
+	info.ExpiresAt = now.Add(l.options.TTL)
+
+	version, err := l.client.UpdateDocument(l.options.IndexName, lockDocumentID, info, l.version)
+	if elasticsearch.IsDocumentConflict(err) {
+		l.lostErr = fmt.Errorf("lock of '%s' has been released, or taken over, by another job", l.options.Owner)
+
+		return l.lostErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	l.info = info
+	l.version = version
+
+	return nil
+}
+
+func (l *Lock) isLost() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.lostErr != nil
+}
+
+// Lost returns an error once the lock is no longer held: taken over by another job, or expired since the last
+// successful heartbeat. The changes must stop, another job may be changing the same indices.
+func (l *Lock) Lost() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.lostErr != nil {
+		return l.lostErr
+	}
+
+	if time.Now().After(l.info.ExpiresAt) {
+		return fmt.Errorf(
+			"lock of '%s' expired at %s, it could not be extended",
+			l.options.Owner,
+			l.info.ExpiresAt.Format(time.RFC3339),
+		)
+	}
+
+	return nil
+}
+
+// Release stops extending the lock and deletes it, unless it has been lost in the meantime.
+func (l *Lock) Release() error {
+	close(l.stop)
+	<-l.stopped
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.lostErr != nil {
+		return l.lostErr
+	}
+
+	err := l.client.DeleteDocument(l.options.IndexName, lockDocumentID, l.version)
+	if elasticsearch.IsDocumentConflict(err) {
+		return fmt.Errorf("lock of '%s' has been taken over by another job", l.options.Owner)
+	}
+
+	return err
+}
+
+// Status returns the current holder of the lock, nil when the cluster isn't locked.
+func (l *Lock) Status() (*LockInfo, error) {
+	info, _, err := l.get()
+
+	return info, err
+}
+
+// ForceRelease deletes the lock whoever holds it, and returns the holder. It is meant for stuck jobs.
+func (l *Lock) ForceRelease() (*LockInfo, error) {
+	info, document, err := l.get()
+	if err != nil || document == nil {
+		return info, err
+	}
+
+	if err := l.client.DeleteDocument(l.options.IndexName, lockDocumentID, document.Version); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (l *Lock) get() (*LockInfo, *elasticsearch.Document, error) {
+	document, err := l.client.GetDocument(l.options.IndexName, lockDocumentID)
+	if err != nil || document == nil {
+		return nil, nil, err
+	}
+
+	info := &LockInfo{}
+	if err := json.Unmarshal(document.Source, info); err != nil {
+		return nil, nil, err
+	}
+
+	return info, document, nil
+}
+
+func lockIndexConfiguration() configuration.Index {
+	return configuration.New(
+		configuration.Mappings{
+			"properties": map[string]interface{}{
+				"owner":        map[string]interface{}{"type": "keyword"},
+				"acquired_at":  map[string]interface{}{"type": "date"},
+				"heartbeat_at": map[string]interface{}{"type": "date"},
+				"expires_at":   map[string]interface{}{"type": "date"},
+			},
+		},
+		configuration.Settings{
+			"number_of_shards":     "1",
+			"auto_expand_replicas": "0-1",
+		},
+	)
+}
@@ -0,0 +1,236 @@
+package action_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+const lockIndexName = ".stretchy-lock"
+const lockOwner = "ci-job-1"
+
+func getLockOptions() action.LockOptions {
+	return action.LockOptions{
+		IndexName:         lockIndexName,
+		Owner:             lockOwner,
+		TTL:               time.Minute,
+		HeartbeatInterval: time.Hour,
+	}
+}
+
+func getLockDocument(t *testing.T, owner string, acquiredAt time.Time) *elasticsearch.Document {
+	source, err := json.Marshal(action.LockInfo{
+		Owner:       owner,
+		AcquiredAt:  acquiredAt,
+		HeartbeatAt: acquiredAt,
+		ExpiresAt:   acquiredAt.Add(time.Minute),
+	})
+	assert.NoError(t, err)
+
+	return &elasticsearch.Document{
+		Source:  source,
+		Version: elasticsearch.DocumentVersion{SeqNo: 4, PrimaryTerm: 1},
+	}
+}
+
+func TestLock_AcquireAndRelease(t *testing.T) {
+	now := time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	version := elasticsearch.DocumentVersion{SeqNo: 1, PrimaryTerm: 1}
+
+	client := elasticsearch.NewMockClient()
+	client.On("IndexExist", lockIndexName).Return(false, nil)
+	client.On("CreateIndex", lockIndexName, mock.Anything).Return(nil)
+	client.On("CreateDocument", lockIndexName, "apply", action.LockInfo{
+		Owner:       lockOwner,
+		AcquiredAt:  now,
+		HeartbeatAt: now,
+		ExpiresAt:   now.Add(time.Minute),
+	}).Return(version, nil)
+	client.On("DeleteDocument", lockIndexName, "apply", version).Return(nil)
+
+	lock := action.NewLock(client, getLockOptions())
+
+	assert.NoError(t, lock.Acquire())
+	assert.NoError(t, lock.Release())
+
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestLock_Acquire_Held(t *testing.T) {
+	now := time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	client := elasticsearch.NewMockClient()
+	client.On("IndexExist", lockIndexName).Return(true, nil)
+	client.On("CreateDocument", lockIndexName, "apply", mock.Anything).
+		Return(elasticsearch.DocumentVersion{}, &elasticsearch.DocumentConflictError{})
+	client.On("GetDocument", lockIndexName, "apply").
+		Return(getLockDocument(t, "ci-job-2", now.Add(-30*time.Second)), nil)
+
+	err := action.NewLock(client, getLockOptions()).Acquire()
+
+	assert.EqualError(
+		t,
+		err,
+		"cluster is locked by 'ci-job-2' since 2020-05-15T08:59:30Z, the lock expires at 2020-05-15T09:00:30Z",
+	)
+
+	client.AssertNotCalled(t, "UpdateDocument", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLock_Acquire_Expired(t *testing.T) {
+	now := time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	expiredDocument := getLockDocument(t, "ci-job-2", now.Add(-2*time.Minute))
+	version := elasticsearch.DocumentVersion{SeqNo: 5, PrimaryTerm: 1}
+
+	client := elasticsearch.NewMockClient()
+	client.On("IndexExist", lockIndexName).Return(true, nil)
+	client.On("CreateDocument", lockIndexName, "apply", mock.Anything).
+		Return(elasticsearch.DocumentVersion{}, &elasticsearch.DocumentConflictError{})
+	client.On("GetDocument", lockIndexName, "apply").Return(expiredDocument, nil)
+	client.On("UpdateDocument", lockIndexName, "apply", mock.Anything, expiredDocument.Version).
+		Return(version, nil)
+	client.On("DeleteDocument", lockIndexName, "apply", version).Return(nil)
+
+	lock := action.NewLock(client, getLockOptions())
+
+	assert.NoError(t, lock.Acquire())
+	assert.NoError(t, lock.Release())
+
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestLock_Acquire_TakenOverConcurrently(t *testing.T) {
+	now := time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	client := elasticsearch.NewMockClient()
+	client.On("IndexExist", lockIndexName).Return(true, nil)
+	client.On("CreateDocument", lockIndexName, "apply", mock.Anything).
+		Return(elasticsearch.DocumentVersion{}, &elasticsearch.DocumentConflictError{})
+	client.On("GetDocument", lockIndexName, "apply").
+		Return(getLockDocument(t, "ci-job-2", now.Add(-2*time.Minute)), nil)
+	client.On("UpdateDocument", lockIndexName, "apply", mock.Anything, mock.Anything).
+		Return(elasticsearch.DocumentVersion{}, &elasticsearch.DocumentConflictError{})
+
+	err := action.NewLock(client, getLockOptions()).Acquire()
+
+	assert.EqualError(t, err, "cluster has been locked concurrently by another job")
+}
+
+func TestLock_Heartbeat(t *testing.T) {
+	now := time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	acquiredVersion := elasticsearch.DocumentVersion{SeqNo: 1, PrimaryTerm: 1}
+	extendedVersion := elasticsearch.DocumentVersion{SeqNo: 2, PrimaryTerm: 1}
+
+	client := elasticsearch.NewMockClient()
+	client.On("IndexExist", lockIndexName).Return(true, nil)
+	client.On("CreateDocument", lockIndexName, "apply", mock.Anything).Return(acquiredVersion, nil)
+	client.On("UpdateDocument", lockIndexName, "apply", mock.Anything, acquiredVersion).
+		Return(extendedVersion, nil).
+		Run(func(args mock.Arguments) {
+			info := args.Get(2).(action.LockInfo)
+			assert.Equal(t, now, info.AcquiredAt)
+			assert.Equal(t, now.Add(time.Minute), info.ExpiresAt)
+		})
+	client.On("UpdateDocument", lockIndexName, "apply", mock.Anything, extendedVersion).
+		Return(elasticsearch.DocumentVersion{}, &elasticsearch.DocumentConflictError{})
+
+	lock := action.NewLock(client, getLockOptions())
+
+	assert.NoError(t, lock.Acquire())
+	assert.NoError(t, lock.Heartbeat())
+
+	lostErr := "lock of 'ci-job-1' has been released, or taken over, by another job"
+	assert.EqualError(t, lock.Heartbeat(), lostErr)
+	assert.EqualError(t, lock.Release(), lostErr)
+
+	client.AssertNotCalled(t, "DeleteDocument", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLock_StatusAndForceRelease(t *testing.T) {
+	acquiredAt := time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC)
+	document := getLockDocument(t, "ci-job-2", acquiredAt)
+
+	client := elasticsearch.NewMockClient()
+	client.On("GetDocument", lockIndexName, "apply").Return(document, nil).Twice()
+	client.On("GetDocument", lockIndexName, "apply").Return((*elasticsearch.Document)(nil), nil)
+	client.On("DeleteDocument", lockIndexName, "apply", document.Version).Return(nil).Once()
+
+	lock := action.NewLock(client, getLockOptions())
+
+	info, err := lock.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, "ci-job-2", info.Owner)
+	assert.Equal(t, acquiredAt, info.AcquiredAt.UTC())
+
+	info, err = lock.ForceRelease()
+	assert.NoError(t, err)
+	assert.Equal(t, "ci-job-2", info.Owner)
+
+	info, err = lock.Status()
+	assert.NoError(t, err)
+	assert.Nil(t, info)
+
+	info, err = lock.ForceRelease()
+	assert.NoError(t, err)
+	assert.Nil(t, info)
+
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestLock_Lost(t *testing.T) {
+	now := time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	acquiredVersion := elasticsearch.DocumentVersion{SeqNo: 1, PrimaryTerm: 1}
+
+	client := elasticsearch.NewMockClient()
+	client.On("IndexExist", lockIndexName).Return(true, nil)
+	client.On("CreateDocument", lockIndexName, "apply", mock.Anything).Return(acquiredVersion, nil)
+	client.On("UpdateDocument", lockIndexName, "apply", mock.Anything, acquiredVersion).
+		Return(elasticsearch.DocumentVersion{}, errors.New("connection refused")).Once()
+	client.On("UpdateDocument", lockIndexName, "apply", mock.Anything, acquiredVersion).
+		Return(elasticsearch.DocumentVersion{}, &elasticsearch.DocumentConflictError{})
+
+	lock := action.NewLock(client, getLockOptions())
+
+	assert.NoError(t, lock.Acquire())
+	assert.NoError(t, lock.Lost())
+
+	// The heartbeat fails until the lock expires.
+	assert.EqualError(t, lock.Heartbeat(), "connection refused")
+	assert.NoError(t, lock.Lost())
+
+	now = now.Add(2 * time.Minute)
+	assert.EqualError(t, lock.Lost(), "lock of 'ci-job-1' expired at 2020-05-15T09:01:00Z, it could not be extended")
+
+	lostErr := "lock of 'ci-job-1' has been released, or taken over, by another job"
+	assert.EqualError(t, lock.Heartbeat(), lostErr)
+	assert.EqualError(t, lock.Lost(), lostErr)
+}
@@ -0,0 +1,121 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+const planFormatVersion = 1
+
+// Plan is a reviewable snapshot of a CompareResultCollection that can be executed later on.
+type Plan struct {
+	FormatVersion int                     `json:"format_version"`
+	CreatedAt     time.Time               `json:"created_at"`
+	Results       CompareResultCollection `json:"results"`
+}
+
+func NewPlan(compareResultCollection CompareResultCollection) *Plan {
+	return &Plan{
+		FormatVersion: planFormatVersion,
+		CreatedAt:     time.Now(),
+		Results:       compareResultCollection,
+	}
+}
+
+func LoadPlan(path string) (*Plan, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	if err := json.Unmarshal(content, plan); err != nil {
+		return nil, fmt.Errorf("plan '%s': %s", path, err)
+	}
+
+	if plan.FormatVersion != planFormatVersion {
+		return nil, fmt.Errorf("plan '%s': unsupported format version '%d'", path, plan.FormatVersion)
+	}
+
+	return plan, nil
+}
+
+func (p *Plan) Save(path string) error {
+	content, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, content, 0600)
+}
+
+// Verify checks that the cluster is still in the state the plan has been computed on.
+func (p *Plan) Verify(client elasticsearch.Client) error {
+	for _, compareResult := range p.Results {
+		if err := verifyCompareResult(client, compareResult); err != nil {
+			return fmt.Errorf("plan is outdated: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func verifyCompareResult(client elasticsearch.Client, compareResult CompareResult) error {
+	aliasExist, err := client.AliasExist(compareResult.AliasName)
+	if err != nil {
+		return err
+	}
+
+	if compareResult.CurrentIndexName == "" {
+		if aliasExist {
+			return fmt.Errorf("alias '%s' has been created after the plan", compareResult.AliasName)
+		}
+
+		return nil
+	}
+
+	if !aliasExist {
+		return fmt.Errorf("alias '%s' doesn't exist anymore", compareResult.AliasName)
+	}
+
+	currentIndexName, err := client.GetAliasedIndex(compareResult.AliasName)
+	if err != nil {
+		return err
+	}
+
+	if currentIndexName != compareResult.CurrentIndexName {
+		return fmt.Errorf(
+			"alias '%s' targets index '%s' instead of '%s'",
+			compareResult.AliasName,
+			currentIndexName,
+			compareResult.CurrentIndexName,
+		)
+	}
+
+	currentConfig, err := client.GetIndexConfiguration(currentIndexName)
+	if err != nil {
+		return err
+	}
+
+	// The provenance metadata is not part of the compared configuration.
+	currentConfig, _ = currentConfig.ExtractMetadata()
+
+	changes, err := compareResult.CurrentConfig.Diff(currentConfig)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) > 0 {
+		return fmt.Errorf(
+			"configuration of index '%s' has changed since the plan (%d changes)",
+			currentIndexName,
+			len(changes),
+		)
+	}
+
+	return nil
+}
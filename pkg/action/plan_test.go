@@ -0,0 +1,151 @@
+package action_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+func getPlanCompareResultCollection() action.CompareResultCollection {
+	return action.CompareResultCollection{
+		action.CompareResult{
+			AliasName: createAliasName,
+			NewConfig: getConfiguration1(),
+			Result:    strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
+		},
+		action.CompareResult{
+			AliasName:        migrateAliasName,
+			CurrentIndexName: currentMigrateIndexName,
+			CurrentConfig:    getConfiguration1(),
+			NewConfig:        getConfiguration2(),
+			Result: strategy.NewIndexVoterResult(
+				strategy.IndexDecisionMigrate,
+				configuration.ChangeCollection{
+					configuration.Change{
+						Type: configuration.ChangeTypeCreate,
+						Path: []string{"mappings", "properties", "updated_at"},
+						From: nil,
+						To: map[string]interface{}{
+							"type": "date",
+						},
+					},
+				},
+			),
+		},
+	}
+}
+
+func TestPlan_SaveAndLoad(t *testing.T) {
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+
+	plan := action.NewPlan(getPlanCompareResultCollection())
+	assert.NoError(t, plan.Save(planPath))
+
+	loadedPlan, err := action.LoadPlan(planPath)
+	assert.NoError(t, err)
+
+	assert.Len(t, loadedPlan.Results, 2)
+	assert.True(t, plan.CreatedAt.Equal(loadedPlan.CreatedAt))
+
+	for i, compareResult := range loadedPlan.Results {
+		expected := plan.Results[i]
+
+		assert.Equal(t, expected.AliasName, compareResult.AliasName)
+		assert.Equal(t, expected.CurrentIndexName, compareResult.CurrentIndexName)
+		assert.Equal(t, expected.Result.Action(), compareResult.Result.Action())
+		assert.Len(t, compareResult.Result.Changes(), len(expected.Result.Changes()))
+
+		changes, err := expected.NewConfig.Diff(compareResult.NewConfig)
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	}
+
+	assert.Equal(
+		t,
+		[]string{"mappings", "properties", "updated_at"},
+		loadedPlan.Results[1].Result.Changes()[0].Path,
+	)
+	assert.Equal(t, configuration.ChangeTypeCreate, loadedPlan.Results[1].Result.Changes()[0].Type)
+}
+
+func TestLoadPlan_Errors(t *testing.T) {
+	_, err := action.LoadPlan(filepath.Join(t.TempDir(), "not-existing.json"))
+	assert.Error(t, err)
+
+	_, err = action.LoadPlan(getScenarioPath(t, "json-syntax-error/test-a.json"))
+	assert.Error(t, err)
+}
+
+func TestPlan_Verify(t *testing.T) {
+	testCases := []struct {
+		name          string
+		aliasExist    bool
+		aliasedIndex  string
+		currentConfig configuration.Index
+		expectError   bool
+	}{
+		{
+			name:          "cluster unchanged",
+			aliasExist:    true,
+			aliasedIndex:  currentMigrateIndexName,
+			currentConfig: getConfiguration1(),
+			expectError:   false,
+		},
+		{
+			name:          "alias targets another index",
+			aliasExist:    true,
+			aliasedIndex:  "another-index",
+			currentConfig: getConfiguration1(),
+			expectError:   true,
+		},
+		{
+			name:          "configuration changed",
+			aliasExist:    true,
+			aliasedIndex:  currentMigrateIndexName,
+			currentConfig: getConfiguration2(),
+			expectError:   true,
+		},
+		{
+			name:        "alias removed",
+			aliasExist:  false,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := elasticsearch.NewMockClient()
+			client.On("AliasExist", createAliasName).Return(false, nil)
+			client.On("AliasExist", migrateAliasName).Return(tc.aliasExist, nil)
+			client.On("GetAliasedIndex", migrateAliasName).Return(tc.aliasedIndex, nil)
+			client.On("GetIndexConfiguration", tc.aliasedIndex).Return(tc.currentConfig, nil)
+
+			err := action.NewPlan(getPlanCompareResultCollection()).Verify(client)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			client.AssertCalled(t, "GetIndexConfiguration", currentMigrateIndexName)
+		})
+	}
+}
+
+func TestPlan_Verify_AliasCreatedAfterPlan(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	client.On("AliasExist", createAliasName).Return(true, nil)
+
+	err := action.NewPlan(getPlanCompareResultCollection()).Verify(client)
+	assert.Error(t, err)
+
+	mock.AssertExpectationsForObjects(t, client)
+}
@@ -0,0 +1,226 @@
+package action
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+// PruneMode defines what is done with the index versions beyond the retention.
+type PruneMode int
+
+const (
+	PruneModeDelete PruneMode = iota
+	PruneModeClose
+	PruneModeReadOnly
+)
+
+func (m PruneMode) String() string {
+	return pruneModeNames()[m]
+}
+
+func pruneModeNames() []string {
+	return []string{"delete", "close", "read-only"}
+}
+
+func NewPruneModeFromString(mode string) (PruneMode, error) {
+	for i, name := range pruneModeNames() {
+		if name == mode {
+			return PruneMode(i), nil
+		}
+	}
+
+	return PruneModeDelete, fmt.Errorf("unknown prune mode '%s'", mode)
+}
+
+// PruneOptions configures the retention of the index versions.
+type PruneOptions struct {
+	// Keep is the number of previous versions kept besides the aliased index.
+	Keep int
+	Mode PruneMode
+}
+
+// PruneResult lists the versions of an alias index.
+type PruneResult struct {
+	AliasName        string
+	CurrentIndexName string
+	Kept             []elasticsearch.IndexInfo
+	Pruned           []elasticsearch.IndexInfo
+	// Orphans are newer than the aliased index, they are usually left behind by a failed migration.
+	Orphans []elasticsearch.IndexInfo
+	// Unmanaged look like versions but have no stretchy metadata, e.g. 'logs-2024' for alias 'logs'.
+	// They are never pruned.
+	Unmanaged []elasticsearch.IndexInfo
+}
+
+type PruneResultCollection []PruneResult
+
+type Prune struct {
+	client      elasticsearch.Client
+	indexPrefix string
+	options     PruneOptions
+}
+
+func NewPrune(
+	client elasticsearch.Client,
+	indexPrefix string,
+	options PruneOptions,
+) *Prune {
+	return &Prune{
+		client:      client,
+		indexPrefix: indexPrefix,
+		options:     options,
+	}
+}
+
+// Prepare finds the versions of the alias index that are beyond the retention.
+func (p *Prune) Prepare(indexName string) (PruneResult, error) {
+	pruneResult := PruneResult{
+		AliasName: elasticsearch.ResolveAliasName(p.indexPrefix, indexName),
+	}
+
+	if p.options.Keep < 0 {
+		return pruneResult, fmt.Errorf("the number of versions to keep cannot be negative, got %d", p.options.Keep)
+	}
+
+	versions, err := elasticsearch.ListIndexVersions(p.client, pruneResult.AliasName)
+	if err != nil {
+		return pruneResult, err
+	}
+
+	aliasExist, err := p.client.AliasExist(pruneResult.AliasName)
+	if err != nil {
+		return pruneResult, err
+	}
+
+	// Without an alias there is no way to know which version is in use.
+	if !aliasExist {
+		pruneResult.Orphans = versions
+
+		return pruneResult, nil
+	}
+
+	if pruneResult.CurrentIndexName, err = p.client.GetAliasedIndex(pruneResult.AliasName); err != nil {
+		return pruneResult, err
+	}
+
+	currentPosition := elasticsearch.IndexVersionPosition(versions, pruneResult.CurrentIndexName)
+
+	// When the aliased index isn't a version, nothing tells which versions are older: they are only reported.
+	if currentPosition < 0 {
+		pruneResult.Orphans = versions
+
+		return pruneResult, nil
+	}
+
+	pruneResult.Orphans = versions[currentPosition+1:]
+	previousVersions := []elasticsearch.IndexInfo{}
+
+	for _, index := range versions[:currentPosition] {
+		managed, err := p.isManaged(index.Name)
+		if err != nil {
+			return pruneResult, err
+		}
+
+		if !managed {
+			pruneResult.Unmanaged = append(pruneResult.Unmanaged, index)
+
+			continue
+		}
+
+		previousVersions = append(previousVersions, index)
+	}
+
+	prunedCount := len(previousVersions) - p.options.Keep
+	if prunedCount < 0 {
+		prunedCount = 0
+	}
+
+	pruneResult.Pruned = previousVersions[:prunedCount]
+	pruneResult.Kept = previousVersions[prunedCount:]
+
+	return pruneResult, nil
+}
+
+// isManaged tells whether the index has been created by stretchy, according to its metadata.
+func (p *Prune) isManaged(indexName string) (bool, error) {
+	index, err := p.client.GetIndexConfiguration(indexName)
+	if err != nil {
+		return false, err
+	}
+
+	_, metadata := index.ExtractMetadata()
+
+	return metadata != nil, nil
+}
+
+func (p *Prune) PrepareAll(indexCollection configuration.IndexCollection) (PruneResultCollection, error) {
+	indexNames := make([]string, 0, len(indexCollection))
+	for indexName := range indexCollection {
+		indexNames = append(indexNames, indexName)
+	}
+
+	sort.Strings(indexNames)
+
+	pruneResultCollection := PruneResultCollection{}
+
+	for _, indexName := range indexNames {
+		pruneResult, err := p.Prepare(indexName)
+		if err != nil {
+			return nil, err
+		}
+
+		pruneResultCollection = append(pruneResultCollection, pruneResult)
+	}
+
+	return pruneResultCollection, nil
+}
+
+// Prune deletes, closes or marks as read-only the pruned versions, the aliased index is never touched.
+func (p *Prune) Prune(pruneResult PruneResult) error {
+	for _, index := range pruneResult.Pruned {
+		if index.Name == pruneResult.CurrentIndexName {
+			return fmt.Errorf("refusing to prune index '%s' targeted by alias '%s'", index.Name, pruneResult.AliasName)
+		}
+
+		if err := p.pruneIndex(index.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Prune) pruneIndex(indexName string) error {
+	switch p.options.Mode {
+	case PruneModeDelete:
+		return p.client.DeleteIndex(indexName)
+	case PruneModeClose:
+		return p.client.CloseIndex(indexName)
+	case PruneModeReadOnly:
+		return p.client.UpdateIndexSettings(
+			indexName,
+			configuration.Settings{
+				"index": map[string]interface{}{
+					"blocks": map[string]interface{}{
+						"write": true,
+					},
+				},
+			},
+		)
+	}
+
+	return fmt.Errorf("unknown prune mode '%s'", p.options.Mode.String())
+}
+
+func (p *Prune) PruneAll(pruneResultCollection PruneResultCollection) error {
+	for _, pruneResult := range pruneResultCollection {
+		if err := p.Prune(pruneResult); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package action_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+func pruneVersions() []elasticsearch.IndexInfo {
+	return []elasticsearch.IndexInfo{
+		{Name: "stretchy-products-100"},
+		{Name: "stretchy-products-200"},
+		{Name: "stretchy-products-300"},
+		{Name: "stretchy-products-400"},
+		{Name: "stretchy-products-500"},
+	}
+}
+
+func TestPrune_Prepare(t *testing.T) {
+	testCases := []struct {
+		name             string
+		keep             int
+		currentIndexName string
+		expectedKept     []string
+		expectedPruned   []string
+		expectedOrphans  []string
+	}{
+		{
+			name:             "keep one",
+			keep:             1,
+			currentIndexName: "stretchy-products-400",
+			expectedKept:     []string{"stretchy-products-300"},
+			expectedPruned:   []string{"stretchy-products-100", "stretchy-products-200"},
+			expectedOrphans:  []string{"stretchy-products-500"},
+		},
+		{
+			name:             "keep none",
+			keep:             0,
+			currentIndexName: "stretchy-products-500",
+			expectedKept:     []string{},
+			expectedPruned: []string{
+				"stretchy-products-100",
+				"stretchy-products-200",
+				"stretchy-products-300",
+				"stretchy-products-400",
+			},
+			expectedOrphans: []string{},
+		},
+		{
+			name:             "keep more than existing",
+			keep:             10,
+			currentIndexName: "stretchy-products-200",
+			expectedKept:     []string{"stretchy-products-100"},
+			expectedPruned:   []string{},
+			expectedOrphans:  []string{"stretchy-products-300", "stretchy-products-400", "stretchy-products-500"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := elasticsearch.NewMockClient()
+			client.On("ListIndices", "stretchy-products-*").Return(pruneVersions(), nil)
+			client.On("AliasExist", "stretchy-products").Return(true, nil)
+			client.On("GetAliasedIndex", "stretchy-products").Return(tc.currentIndexName, nil)
+			client.On("GetIndexConfiguration", mock.Anything).Return(withMetadata(t, configuration.Index{}), nil)
+
+			pruneResult, err := action.NewPrune(
+				client,
+				"stretchy",
+				action.PruneOptions{Keep: tc.keep},
+			).Prepare("products")
+
+			assert.NoError(t, err)
+			assert.Equal(t, "stretchy-products", pruneResult.AliasName)
+			assert.Equal(t, tc.currentIndexName, pruneResult.CurrentIndexName)
+			assert.Equal(t, tc.expectedKept, indexNames(pruneResult.Kept))
+			assert.Equal(t, tc.expectedPruned, indexNames(pruneResult.Pruned))
+			assert.Equal(t, tc.expectedOrphans, indexNames(pruneResult.Orphans))
+		})
+	}
+}
+
+func TestPrune_Prepare_MissingAlias(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	client.On("ListIndices", "stretchy-products-*").Return(pruneVersions()[:1], nil)
+	client.On("AliasExist", "stretchy-products").Return(false, nil)
+
+	pruneResult, err := action.NewPrune(client, "stretchy", action.PruneOptions{}).Prepare("products")
+
+	assert.NoError(t, err)
+	assert.Empty(t, pruneResult.Pruned)
+	assert.Len(t, pruneResult.Orphans, 1)
+}
+
+func TestPrune_Prepare_UnknownCurrentIndex(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	client.On("ListIndices", "stretchy-products-*").Return(pruneVersions(), nil)
+	client.On("AliasExist", "stretchy-products").Return(true, nil)
+	client.On("GetAliasedIndex", "stretchy-products").Return("stretchy-products", nil)
+
+	pruneResult, err := action.NewPrune(client, "stretchy", action.PruneOptions{}).Prepare("products")
+
+	assert.NoError(t, err)
+	assert.Empty(t, pruneResult.Pruned)
+	assert.Empty(t, pruneResult.Kept)
+	assert.Len(t, pruneResult.Orphans, 5)
+}
+
+func TestPrune_Prepare_Unmanaged(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	client.On("ListIndices", "stretchy-products-*").Return(pruneVersions(), nil)
+	client.On("AliasExist", "stretchy-products").Return(true, nil)
+	client.On("GetAliasedIndex", "stretchy-products").Return("stretchy-products-500", nil)
+	client.On("GetIndexConfiguration", "stretchy-products-100").Return(withMetadata(t, configuration.Index{}), nil)
+	client.On("GetIndexConfiguration", "stretchy-products-200").Return(configuration.Index{}, nil)
+	client.On("GetIndexConfiguration", "stretchy-products-300").Return(withMetadata(t, configuration.Index{}), nil)
+	client.On("GetIndexConfiguration", "stretchy-products-400").Return(configuration.Index{}, nil)
+
+	pruneResult, err := action.NewPrune(client, "stretchy", action.PruneOptions{Keep: 1}).Prepare("products")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"stretchy-products-100"}, indexNames(pruneResult.Pruned))
+	assert.Equal(t, []string{"stretchy-products-300"}, indexNames(pruneResult.Kept))
+	assert.Equal(t, []string{"stretchy-products-200", "stretchy-products-400"}, indexNames(pruneResult.Unmanaged))
+}
+
+func TestPrune_Prune(t *testing.T) {
+	testCases := []struct {
+		mode          action.PruneMode
+		expectedSetup func(client *elasticsearch.MockClient, indexName string)
+	}{
+		{
+			mode: action.PruneModeDelete,
+			expectedSetup: func(client *elasticsearch.MockClient, indexName string) {
+				client.On("DeleteIndex", indexName).Return(nil)
+			},
+		},
+		{
+			mode: action.PruneModeClose,
+			expectedSetup: func(client *elasticsearch.MockClient, indexName string) {
+				client.On("CloseIndex", indexName).Return(nil)
+			},
+		},
+		{
+			mode: action.PruneModeReadOnly,
+			expectedSetup: func(client *elasticsearch.MockClient, indexName string) {
+				client.On(
+					"UpdateIndexSettings",
+					indexName,
+					configuration.Settings{
+						"index": map[string]interface{}{
+							"blocks": map[string]interface{}{"write": true},
+						},
+					},
+				).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.mode.String(), func(t *testing.T) {
+			client := elasticsearch.NewMockClient()
+			tc.expectedSetup(client, "stretchy-products-100")
+
+			err := action.NewPrune(client, "stretchy", action.PruneOptions{Mode: tc.mode}).Prune(action.PruneResult{
+				AliasName:        "stretchy-products",
+				CurrentIndexName: "stretchy-products-200",
+				Pruned:           pruneVersions()[:1],
+			})
+
+			assert.NoError(t, err)
+			mock.AssertExpectationsForObjects(t, client)
+		})
+	}
+}
+
+func TestPrune_Prune_NeverTouchesTheAliasedIndex(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+
+	err := action.NewPrune(client, "stretchy", action.PruneOptions{}).Prune(action.PruneResult{
+		AliasName:        "stretchy-products",
+		CurrentIndexName: "stretchy-products-100",
+		Pruned:           pruneVersions()[:1],
+	})
+
+	assert.Error(t, err)
+	client.AssertNotCalled(t, "DeleteIndex", "stretchy-products-100")
+}
+
+func TestNewPruneModeFromString(t *testing.T) {
+	for _, mode := range []action.PruneMode{action.PruneModeDelete, action.PruneModeClose, action.PruneModeReadOnly} {
+		parsedMode, err := action.NewPruneModeFromString(mode.String())
+		assert.NoError(t, err)
+		assert.Equal(t, mode, parsedMode)
+	}
+
+	_, err := action.NewPruneModeFromString("archive")
+	assert.Error(t, err)
+}
+
+func indexNames(indices []elasticsearch.IndexInfo) []string {
+	names := []string{}
+
+	for _, index := range indices {
+		names = append(names, index.Name)
+	}
+
+	return names
+}
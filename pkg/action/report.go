@@ -0,0 +1,139 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/strategy"
+	"gopkg.in/yaml.v3"
+)
+
+// ReportFormatVersion is increased on each breaking change of the report schema.
+const ReportFormatVersion = 1
+
+type ReportFormat int
+
+const (
+	ReportFormatJSON ReportFormat = iota
+	ReportFormatYAML
+	ReportFormatMarkdown
+)
+
+func (rf ReportFormat) String() string {
+	return reportFormatNames()[rf]
+}
+
+func reportFormatNames() []string {
+	return []string{"json", "yaml", "markdown"}
+}
+
+func NewReportFormatFromString(format string) (ReportFormat, error) {
+	for i, name := range reportFormatNames() {
+		if name == format {
+			return ReportFormat(i), nil
+		}
+	}
+
+	return ReportFormatJSON, fmt.Errorf("unknown report format '%s'", format)
+}
+
+// Report is the machine-readable description of the compare results, and of their outcomes once applied.
+type Report struct {
+	FormatVersion int            `json:"format_version" yaml:"format_version"`
+	Results       []ReportResult `json:"results" yaml:"results"`
+}
+
+type ReportResult struct {
+	ConfigurationName string                         `json:"configuration_name" yaml:"configuration_name"`
+	AliasName         string                         `json:"alias_name" yaml:"alias_name"`
+	CurrentIndexName  string                         `json:"current_index_name" yaml:"current_index_name"`
+	Action            strategy.IndexAction           `json:"action" yaml:"action"`
+	Changes           configuration.ChangeCollection `json:"changes" yaml:"changes"`
+	Drift             bool                           `json:"drift" yaml:"drift"`
+	// Outcome is nil when the result hasn't been applied.
+	Outcome *ReportOutcome `json:"outcome" yaml:"outcome"`
+}
+
+type ReportOutcome struct {
+	Outcome        HistoryOutcome       `json:"outcome" yaml:"outcome"`
+	Error          string               `json:"error,omitempty" yaml:"error,omitempty"`
+	IndexName      string               `json:"index_name" yaml:"index_name"`
+	StartedAt      time.Time            `json:"started_at" yaml:"started_at"`
+	DurationMillis int64                `json:"duration_ms" yaml:"duration_ms"`
+	Reindex        *HistoryReindexStats `json:"reindex,omitempty" yaml:"reindex,omitempty"`
+}
+
+// NewReport describes the compare results, the outcomes are matched with the results in order:
+// the results after the last outcome haven't been applied.
+func NewReport(compareResultCollection CompareResultCollection, outcomes ApplyOutcomeCollection) *Report {
+	report := &Report{
+		FormatVersion: ReportFormatVersion,
+		Results:       []ReportResult{},
+	}
+
+	for i, compareResult := range compareResultCollection {
+		result := ReportResult{
+			ConfigurationName: compareResult.ConfigurationName,
+			AliasName:         compareResult.AliasName,
+			CurrentIndexName:  compareResult.CurrentIndexName,
+			Action:            compareResult.Result.Action(),
+			Changes:           compareResult.Result.Changes(),
+			Drift:             compareResult.Drift,
+		}
+
+		if result.Changes == nil {
+			result.Changes = configuration.ChangeCollection{}
+		}
+
+		if i < len(outcomes) {
+			result.Outcome = newReportOutcome(outcomes[i])
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+func newReportOutcome(outcome ApplyOutcome) *ReportOutcome {
+	reportOutcome := &ReportOutcome{
+		Outcome:        HistoryOutcomeSuccess,
+		IndexName:      outcome.IndexName,
+		StartedAt:      outcome.StartedAt,
+		DurationMillis: int64(outcome.Duration / time.Millisecond),
+		Reindex:        newHistoryReindexStats(outcome.ReindexTasks),
+	}
+
+	if outcome.Err != nil {
+		reportOutcome.Outcome = HistoryOutcomeFailure
+		reportOutcome.Error = outcome.Err.Error()
+	}
+
+	return reportOutcome
+}
+
+func (r *Report) Write(writer io.Writer, format ReportFormat) error {
+	switch format {
+	case ReportFormatJSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(r)
+	case ReportFormatYAML:
+		encoder := yaml.NewEncoder(writer)
+		encoder.SetIndent(2)
+
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+
+		return encoder.Close()
+	case ReportFormatMarkdown:
+		return r.writeMarkdown(writer)
+	}
+
+	return fmt.Errorf("unknown report format '%d'", format)
+}
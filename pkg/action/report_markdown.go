@@ -0,0 +1,181 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+// writeMarkdown renders the report for a pull request comment: a summary table,
+// then a collapsible section with the changes of each index.
+func (r *Report) writeMarkdown(writer io.Writer) error {
+	markdown := &strings.Builder{}
+
+	markdown.WriteString("## Stretchy changes\n\n")
+
+	if len(r.Results) == 0 {
+		markdown.WriteString("No configured index.\n")
+	} else {
+		r.writeMarkdownSummary(markdown)
+	}
+
+	for _, result := range r.Results {
+		if len(result.Changes) > 0 || result.Drift || result.hasFailed() {
+			result.writeMarkdownDetails(markdown)
+		}
+	}
+
+	_, err := io.WriteString(writer, markdown.String())
+
+	return err
+}
+
+func (r *Report) writeMarkdownSummary(markdown *strings.Builder) {
+	withOutcome := false
+
+	for _, result := range r.Results {
+		if result.Outcome != nil {
+			withOutcome = true
+		}
+	}
+
+	if withOutcome {
+		markdown.WriteString("| Alias | Decision | Changes | Reindex | Outcome |\n")
+		markdown.WriteString("|-------|----------|---------|---------|---------|\n")
+	} else {
+		markdown.WriteString("| Alias | Decision | Changes | Reindex |\n")
+		markdown.WriteString("|-------|----------|---------|---------|\n")
+	}
+
+	for _, result := range r.Results {
+		reindex := "no"
+		if result.Action == strategy.IndexDecisionMigrate {
+			reindex = "yes"
+		}
+
+		fmt.Fprintf(
+			markdown,
+			"| %s | %s | %d | %s |",
+			markdownCode(result.AliasName),
+			result.markdownDecision(),
+			len(result.Changes),
+			reindex,
+		)
+
+		if withOutcome {
+			fmt.Fprintf(markdown, " %s |", result.markdownOutcome())
+		}
+
+		markdown.WriteString("\n")
+	}
+}
+
+func (rr ReportResult) writeMarkdownDetails(markdown *strings.Builder) {
+	summary := fmt.Sprintf("<code>%s</code>: %s", markdownEscape(rr.AliasName), rr.markdownDecision())
+	if rr.CurrentIndexName != "" {
+		summary += fmt.Sprintf(" of <code>%s</code>", markdownEscape(rr.CurrentIndexName))
+	}
+
+	fmt.Fprintf(markdown, "\n<details>\n<summary>%s</summary>\n\n", summary)
+
+	if rr.Action == strategy.IndexDecisionMigrate {
+		markdown.WriteString("> **Warning**: the documents are reindexed in a new index, then the alias is moved on it.\n\n")
+	}
+
+	if rr.Action == strategy.IndexDecisionUpdateAnalysis {
+		markdown.WriteString("> **Warning**: the index is closed, and unavailable, while its analysis is updated.\n\n")
+	}
+
+	if rr.Drift {
+		markdown.WriteString(
+			"> **Drift**: the index has been changed outside of stretchy since its last apply, " +
+				"or a document has added a dynamically mapped field.\n\n",
+		)
+	}
+
+	if rr.hasFailed() {
+		fmt.Fprintf(markdown, "> **Failure**: %s\n\n", markdownEscape(rr.Outcome.Error))
+	}
+
+	if len(rr.Changes) > 0 {
+		markdown.WriteString("| Change | Path | From | To |\n")
+		markdown.WriteString("|--------|------|------|----|\n")
+
+		for _, change := range rr.Changes {
+			fmt.Fprintf(
+				markdown,
+				"| %s | %s | %s | %s |\n",
+				change.Type.String(),
+				markdownCode(change.FullPath()),
+				markdownValue(change.From),
+				markdownValue(change.To),
+			)
+		}
+
+		markdown.WriteString("\n")
+	}
+
+	markdown.WriteString("</details>\n")
+}
+
+func (rr ReportResult) hasFailed() bool {
+	return rr.Outcome != nil && rr.Outcome.Outcome == HistoryOutcomeFailure
+}
+
+// markdownDecision highlights the decisions which make the index unavailable or recreate it.
+func (rr ReportResult) markdownDecision() string {
+	switch rr.Action {
+	case strategy.IndexDecisionMigrate, strategy.IndexDecisionUpdateAnalysis:
+		return fmt.Sprintf(":warning: **%s**", rr.Action.String())
+	}
+
+	return rr.Action.String()
+}
+
+func (rr ReportResult) markdownOutcome() string {
+	switch {
+	case rr.Outcome == nil:
+		return "not applied"
+	case rr.hasFailed():
+		return ":x: failure"
+	}
+
+	return ":white_check_mark: success"
+}
+
+// markdownValue prints a changed value as JSON, so that objects and lists stay readable.
+func markdownValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	content := &strings.Builder{}
+
+	// The value is escaped for its table cell by markdownCode, not for a script tag.
+	encoder := json.NewEncoder(content)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return markdownEscape(fmt.Sprint(value))
+	}
+
+	return markdownCode(strings.TrimSuffix(content.String(), "\n"))
+}
+
+func markdownCode(value string) string {
+	return "<code>" + markdownEscape(value) + "</code>"
+}
+
+// markdownEscape keeps a value in its table cell, HTML code tags are used instead of backticks for that reason.
+func markdownEscape(value string) string {
+	return strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		"|", "&#124;",
+		"\n", " ",
+	).Replace(value)
+}
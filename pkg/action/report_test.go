@@ -0,0 +1,180 @@
+package action_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+func getReport() *action.Report {
+	migrateOutcome := getMigrateOutcome()
+	migrateOutcome.Err = errors.New("reindex failed")
+
+	createResult := action.CompareResult{
+		ConfigurationName: "orders",
+		AliasName:         "stretchy-orders",
+		Result:            strategy.NewIndexVoterResult(strategy.IndexDecisionCreate, nil),
+	}
+
+	return action.NewReport(
+		action.CompareResultCollection{migrateOutcome.CompareResult, createResult},
+		action.ApplyOutcomeCollection{migrateOutcome},
+	)
+}
+
+func TestNewReport(t *testing.T) {
+	report := getReport()
+
+	assert.Equal(t, action.ReportFormatVersion, report.FormatVersion)
+	assert.Len(t, report.Results, 2)
+
+	migrateResult := report.Results[0]
+	assert.Equal(t, "stretchy-products", migrateResult.AliasName)
+	assert.Equal(t, strategy.IndexDecisionMigrate, migrateResult.Action)
+	assert.Len(t, migrateResult.Changes, 1)
+	assert.Equal(
+		t,
+		&action.ReportOutcome{
+			Outcome:        action.HistoryOutcomeFailure,
+			Error:          "reindex failed",
+			IndexName:      "stretchy-products-100",
+			StartedAt:      migrateResult.Outcome.StartedAt,
+			DurationMillis: 90000,
+			Reindex: &action.HistoryReindexStats{
+				Tasks:          2,
+				Total:          12,
+				Created:        8,
+				Updated:        2,
+				Failures:       1,
+				DurationMillis: 61000,
+			},
+		},
+		migrateResult.Outcome,
+	)
+
+	createResult := report.Results[1]
+	assert.Equal(t, strategy.IndexDecisionCreate, createResult.Action)
+	assert.Equal(t, configuration.ChangeCollection{}, createResult.Changes)
+	assert.Nil(t, createResult.Outcome)
+}
+
+func TestReport_Write(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   action.ReportFormat
+		expected []string
+	}{
+		{
+			name:   "json",
+			format: action.ReportFormatJSON,
+			expected: []string{
+				`"format_version": 1`,
+				`"alias_name": "stretchy-products"`,
+				`"action": "Migrate"`,
+				`"type": "UPDATE"`,
+				`"from": "text"`,
+				`"outcome": "failure"`,
+				`"duration_ms": 90000`,
+				`"outcome": null`,
+			},
+		},
+		{
+			name:   "yaml",
+			format: action.ReportFormatYAML,
+			expected: []string{
+				"format_version: 1",
+				"alias_name: stretchy-products",
+				"action: Migrate",
+				"type: UPDATE",
+				"from: text",
+				"outcome: failure",
+				"duration_ms: 90000",
+				"outcome: null",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+
+			assert.NoError(t, getReport().Write(buffer, tc.format))
+
+			for _, expected := range tc.expected {
+				assert.Contains(t, buffer.String(), expected)
+			}
+		})
+	}
+}
+
+func TestNewReportFormatFromString(t *testing.T) {
+	format, err := action.NewReportFormatFromString("yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, action.ReportFormatYAML, format)
+
+	_, err = action.NewReportFormatFromString("xml")
+	assert.EqualError(t, err, "unknown report format 'xml'")
+}
+
+func TestReport_Write_Markdown(t *testing.T) {
+	buffer := &bytes.Buffer{}
+
+	assert.NoError(t, getReport().Write(buffer, action.ReportFormatMarkdown))
+
+	assert.Equal(
+		t,
+		"## Stretchy changes\n\n"+
+			"| Alias | Decision | Changes | Reindex | Outcome |\n"+
+			"|-------|----------|---------|---------|---------|\n"+
+			"| <code>stretchy-products</code> | :warning: **Migrate** | 1 | yes | :x: failure |\n"+
+			"| <code>stretchy-orders</code> | Create | 0 | no | not applied |\n"+
+			"\n<details>\n"+
+			"<summary><code>stretchy-products</code>: :warning: **Migrate** of <code>stretchy-products-100</code></summary>\n\n"+
+			"> **Warning**: the documents are reindexed in a new index, then the alias is moved on it.\n\n"+
+			"> **Failure**: reindex failed\n\n"+
+			"| Change | Path | From | To |\n"+
+			"|--------|------|------|----|\n"+
+			"| UPDATE | <code>mappings.properties.name.type</code> | <code>\"text\"</code> | <code>\"keyword\"</code> |\n"+
+			"\n</details>\n",
+		buffer.String(),
+	)
+}
+
+func TestReport_Write_MarkdownEscape(t *testing.T) {
+	report := action.NewReport(
+		action.CompareResultCollection{
+			{
+				AliasName: "stretchy-products",
+				Result: strategy.NewIndexVoterResult(
+					strategy.IndexDecisionUpdate,
+					configuration.ChangeCollection{
+						{
+							Type: configuration.ChangeTypeCreate,
+							Path: []string{"settings", "index", "analysis", "filter", "synonyms", "synonyms"},
+							To:   []interface{}{"a|b => <c>"},
+						},
+					},
+				),
+			},
+		},
+		nil,
+	)
+
+	buffer := &bytes.Buffer{}
+
+	assert.NoError(t, report.Write(buffer, action.ReportFormatMarkdown))
+	assert.Contains(
+		t,
+		buffer.String(),
+		"| CREATE | <code>settings.index.analysis.filter.synonyms.synonyms</code> |  | "+
+			"<code>[\"a&#124;b =&gt; &lt;c&gt;\"]</code> |\n",
+	)
+	assert.NotContains(t, buffer.String(), "Outcome")
+}
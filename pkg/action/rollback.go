@@ -0,0 +1,172 @@
+package action
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+// RollbackResult describes the move of an alias back to another version of its index.
+type RollbackResult struct {
+	AliasName        string
+	CurrentIndexName string
+	TargetIndexName  string
+	// TargetWriteBlocked is set when the target doesn't accept writes, e.g. once pruned in read-only mode.
+	// The block is removed before the alias is moved.
+	TargetWriteBlocked bool
+	Changes            configuration.ChangeCollection
+}
+
+// RollbackOutcome describes how a rollback result has been applied.
+type RollbackOutcome struct {
+	RollbackResult RollbackResult
+	StartedAt      time.Time
+	Duration       time.Duration
+	Err            error
+}
+
+type Rollback struct {
+	client  elasticsearch.Client
+	history *History
+}
+
+// NewRollback creates a Rollback, the history is optional.
+func NewRollback(client elasticsearch.Client, history *History) *Rollback {
+	return &Rollback{
+		client:  client,
+		history: history,
+	}
+}
+
+// Prepare resolves the index the alias goes back to, the previous version when targetIndexName is empty,
+// and computes the configuration changes between the two indices.
+// The target must be an open version of the alias.
+func (r *Rollback) Prepare(aliasName string, targetIndexName string) (RollbackResult, error) {
+	currentIndexName, err := r.client.GetAliasedIndex(aliasName)
+	if err != nil {
+		return RollbackResult{}, err
+	}
+
+	versions, err := elasticsearch.ListIndexVersions(r.client, aliasName)
+	if err != nil {
+		return RollbackResult{}, err
+	}
+
+	if targetIndexName == "" {
+		if targetIndexName, err = r.previousIndexName(aliasName, currentIndexName, versions); err != nil {
+			return RollbackResult{}, err
+		}
+	}
+
+	if targetIndexName == currentIndexName {
+		return RollbackResult{}, fmt.Errorf("alias '%s' already targets index '%s'", aliasName, targetIndexName)
+	}
+
+	targetPosition := elasticsearch.IndexVersionPosition(versions, targetIndexName)
+	if targetPosition < 0 {
+		return RollbackResult{}, fmt.Errorf(
+			"index '%s' doesn't exist or isn't a version of alias '%s'",
+			targetIndexName,
+			aliasName,
+		)
+	}
+
+	// A closed index, e.g. pruned in close mode, would fail every request sent through the alias.
+	if versions[targetPosition].Status == "close" {
+		return RollbackResult{}, fmt.Errorf("index '%s' is closed, open it before rolling back to it", targetIndexName)
+	}
+
+	currentConfig, err := r.client.GetIndexConfiguration(currentIndexName)
+	if err != nil {
+		return RollbackResult{}, err
+	}
+
+	targetConfig, err := r.client.GetIndexConfiguration(targetIndexName)
+	if err != nil {
+		return RollbackResult{}, err
+	}
+
+	targetWriteBlocked := isWriteBlocked(targetConfig)
+
+	currentConfig, _ = currentConfig.ExtractMetadata()
+	targetConfig, _ = targetConfig.ExtractMetadata()
+
+	changes, err := currentConfig.Diff(targetConfig)
+	if err != nil {
+		return RollbackResult{}, err
+	}
+
+	return RollbackResult{
+		AliasName:          aliasName,
+		CurrentIndexName:   currentIndexName,
+		TargetIndexName:    targetIndexName,
+		TargetWriteBlocked: targetWriteBlocked,
+		Changes:            changes,
+	}, nil
+}
+
+// Rollback unblocks the writes on the target index, then atomically moves the alias on it.
+func (r *Rollback) Rollback(rollbackResult RollbackResult) error {
+	return r.RollbackWithOutcome(rollbackResult).Err
+}
+
+// RollbackWithOutcome moves the alias and reports what has been done, even when it fails.
+func (r *Rollback) RollbackWithOutcome(rollbackResult RollbackResult) RollbackOutcome {
+	outcome := RollbackOutcome{
+		RollbackResult: rollbackResult,
+		StartedAt:      time.Now(),
+	}
+
+	outcome.Err = r.rollback(rollbackResult)
+	outcome.Duration = time.Now().Sub(outcome.StartedAt)
+
+	return outcome
+}
+
+func (r *Rollback) rollback(rollbackResult RollbackResult) error {
+	if rollbackResult.TargetWriteBlocked {
+		if err := r.client.SetWriteBlock(rollbackResult.TargetIndexName, false); err != nil {
+			return err
+		}
+	}
+
+	return r.client.UpdateAlias(rollbackResult.AliasName, rollbackResult.TargetIndexName)
+}
+
+// previousIndexName returns the index the migration to the current index started from, according to the history.
+// Otherwise it returns the newest version of the alias older than the current index.
+func (r *Rollback) previousIndexName(
+	aliasName string,
+	currentIndexName string,
+	versions []elasticsearch.IndexInfo,
+) (string, error) {
+	if r.history != nil {
+		record, err := r.history.LastMigration(aliasName, currentIndexName)
+		if err != nil {
+			return "", err
+		}
+
+		// The previous index may have been deleted by prune since.
+		if record != nil && elasticsearch.IndexVersionPosition(versions, record.PreviousIndexName) >= 0 {
+			return record.PreviousIndexName, nil
+		}
+	}
+
+	previousVersions := versions
+	if currentPosition := elasticsearch.IndexVersionPosition(versions, currentIndexName); currentPosition >= 0 {
+		previousVersions = versions[:currentPosition]
+	}
+
+	previousIndexName := ""
+	if len(previousVersions) > 0 {
+		previousIndexName = previousVersions[len(previousVersions)-1].Name
+	}
+
+	if previousIndexName == "" {
+		return "", fmt.Errorf("no previous version of alias '%s' found", aliasName)
+	}
+
+	return previousIndexName, nil
+}
@@ -0,0 +1,266 @@
+package action_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+const rollbackAliasName = "index-rollback"
+
+func rollbackVersions() []elasticsearch.IndexInfo {
+	return []elasticsearch.IndexInfo{
+		{Name: "index-rollback-100"},
+		{Name: "index-rollback-200"},
+		{Name: "index-rollback-300"},
+	}
+}
+
+func TestRollback_Prepare(t *testing.T) {
+	testCases := []struct {
+		name             string
+		currentIndexName string
+		to               string
+		expectedIndex    string
+	}{
+		{
+			name:             "previous version",
+			currentIndexName: "index-rollback-300",
+			expectedIndex:    "index-rollback-200",
+		},
+		{
+			name:             "current index is not a version",
+			currentIndexName: "index-rollback",
+			expectedIndex:    "index-rollback-300",
+		},
+		{
+			name:             "explicit version",
+			currentIndexName: "index-rollback-300",
+			to:               "index-rollback-100",
+			expectedIndex:    "index-rollback-100",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			currentConfig := configuration.New(
+				configuration.Mappings{"properties": map[string]interface{}{}},
+				configuration.Settings{},
+			)
+			targetConfig := configuration.New(
+				configuration.Mappings{},
+				configuration.Settings{},
+			)
+
+			client := elasticsearch.NewMockClient()
+			client.On("GetAliasedIndex", rollbackAliasName).Return(tc.currentIndexName, nil)
+			client.On("ListIndices", rollbackAliasName+"-*").Return(rollbackVersions(), nil)
+			client.On("GetIndexConfiguration", tc.currentIndexName).Return(currentConfig, nil)
+			client.On("GetIndexConfiguration", tc.expectedIndex).Return(targetConfig, nil)
+
+			rollbackResult, err := action.NewRollback(client, nil).Prepare(rollbackAliasName, tc.to)
+			assert.NoError(t, err)
+			assert.Equal(t, rollbackAliasName, rollbackResult.AliasName)
+			assert.Equal(t, tc.currentIndexName, rollbackResult.CurrentIndexName)
+			assert.Equal(t, tc.expectedIndex, rollbackResult.TargetIndexName)
+			assert.False(t, rollbackResult.TargetWriteBlocked)
+			assert.Len(t, rollbackResult.Changes, 1)
+		})
+	}
+}
+
+func TestRollback_Prepare_MixedNamingStrategies(t *testing.T) {
+	creationDate := time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	client := elasticsearch.NewMockClient()
+	client.On("GetAliasedIndex", rollbackAliasName).Return("index-rollback-v1", nil)
+	client.On("ListIndices", rollbackAliasName+"-*").Return(
+		[]elasticsearch.IndexInfo{
+			{Name: "index-rollback-v1", CreationDate: creationDate.Add(2 * time.Hour)},
+			{Name: "index-rollback-6f1c0a9e3b2d", CreationDate: creationDate.Add(time.Hour)},
+			{Name: "index-rollback-1589533200", CreationDate: creationDate},
+		},
+		nil,
+	)
+	client.On("GetIndexConfiguration", mock.Anything).Return(configuration.Index{}, nil)
+
+	rollbackResult, err := action.NewRollback(client, nil).Prepare(rollbackAliasName, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "index-rollback-6f1c0a9e3b2d", rollbackResult.TargetIndexName)
+}
+
+func TestRollback_Prepare_WriteBlockedTarget(t *testing.T) {
+	targetConfig := configuration.New(
+		configuration.Mappings{},
+		configuration.Settings{"index": map[string]interface{}{"blocks": map[string]interface{}{"write": "true"}}},
+	)
+
+	client := elasticsearch.NewMockClient()
+	client.On("GetAliasedIndex", rollbackAliasName).Return("index-rollback-300", nil)
+	client.On("ListIndices", rollbackAliasName+"-*").Return(rollbackVersions(), nil)
+	client.On("GetIndexConfiguration", "index-rollback-300").Return(configuration.Index{}, nil)
+	client.On("GetIndexConfiguration", "index-rollback-200").Return(targetConfig, nil)
+
+	rollbackResult, err := action.NewRollback(client, nil).Prepare(rollbackAliasName, "")
+	assert.NoError(t, err)
+	assert.True(t, rollbackResult.TargetWriteBlocked)
+}
+
+func TestRollback_Prepare_FromHistory(t *testing.T) {
+	testCases := []struct {
+		name               string
+		previousIndexExist bool
+		expectedIndex      string
+	}{
+		{
+			name:               "previous index of the last migration",
+			previousIndexExist: true,
+			expectedIndex:      "index-rollback-100",
+		},
+		{
+			name:               "previous index of the last migration pruned",
+			previousIndexExist: false,
+			expectedIndex:      "index-rollback-200",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			record, err := json.Marshal(action.HistoryRecord{
+				AliasName:         rollbackAliasName,
+				Action:            strategy.IndexDecisionMigrate,
+				PreviousIndexName: "index-rollback-100",
+				IndexName:         "index-rollback-300",
+			})
+			assert.NoError(t, err)
+
+			client := elasticsearch.NewMockClient()
+			client.On("GetAliasedIndex", rollbackAliasName).Return("index-rollback-300", nil)
+			client.On("SearchDocuments", historyIndexName, mock.Anything).Return([]json.RawMessage{record}, nil)
+
+			versions := rollbackVersions()
+			if !tc.previousIndexExist {
+				versions = versions[1:]
+			}
+
+			client.On("ListIndices", rollbackAliasName+"-*").Return(versions, nil)
+			client.On("GetIndexConfiguration", mock.Anything).Return(configuration.Index{}, nil)
+
+			history := action.NewHistory(client, getHistoryOptions())
+
+			rollbackResult, err := action.NewRollback(client, history).Prepare(rollbackAliasName, "")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedIndex, rollbackResult.TargetIndexName)
+		})
+	}
+}
+
+func TestRollback_Prepare_Errors(t *testing.T) {
+	testCases := []struct {
+		name             string
+		currentIndexName string
+		to               string
+	}{
+		{
+			name:             "no previous version",
+			currentIndexName: "index-rollback-100",
+		},
+		{
+			name:             "already targeted",
+			currentIndexName: "index-rollback-300",
+			to:               "index-rollback-300",
+		},
+		{
+			name:             "missing target",
+			currentIndexName: "index-rollback-300",
+			to:               "index-rollback-50",
+		},
+		{
+			name:             "target of another alias",
+			currentIndexName: "index-rollback-300",
+			to:               "index-other-100",
+		},
+		{
+			name:             "target which is not a version",
+			currentIndexName: "index-rollback-300",
+			to:               "index-rollback-backup",
+		},
+		{
+			name:             "closed target",
+			currentIndexName: "index-rollback-300",
+			to:               "index-rollback-400",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			versions := append(rollbackVersions(), elasticsearch.IndexInfo{Name: "index-rollback-400", Status: "close"})
+
+			client := elasticsearch.NewMockClient()
+			client.On("GetAliasedIndex", rollbackAliasName).Return(tc.currentIndexName, nil)
+			client.On("ListIndices", rollbackAliasName+"-*").Return(versions, nil)
+
+			_, err := action.NewRollback(client, nil).Prepare(rollbackAliasName, tc.to)
+			assert.Error(t, err)
+
+			client.AssertNotCalled(t, "GetIndexConfiguration", mock.Anything)
+		})
+	}
+}
+
+func TestRollback_Rollback(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	client.On("UpdateAlias", rollbackAliasName, "index-rollback-200").Return(nil)
+
+	err := action.NewRollback(client, nil).Rollback(action.RollbackResult{
+		AliasName:        rollbackAliasName,
+		CurrentIndexName: "index-rollback-300",
+		TargetIndexName:  "index-rollback-200",
+	})
+
+	assert.NoError(t, err)
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestRollback_Rollback_WriteBlockedTarget(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	client.On("SetWriteBlock", "index-rollback-200", false).Return(nil).Once()
+	client.On("UpdateAlias", rollbackAliasName, "index-rollback-200").Return(nil).Once()
+
+	outcome := action.NewRollback(client, nil).RollbackWithOutcome(action.RollbackResult{
+		AliasName:          rollbackAliasName,
+		CurrentIndexName:   "index-rollback-300",
+		TargetIndexName:    "index-rollback-200",
+		TargetWriteBlocked: true,
+	})
+
+	assert.NoError(t, outcome.Err)
+	assert.False(t, outcome.StartedAt.IsZero())
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestRollback_Rollback_UnblockFailure(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	client.On("SetWriteBlock", "index-rollback-200", false).Return(errors.New("forbidden"))
+
+	err := action.NewRollback(client, nil).Rollback(action.RollbackResult{
+		AliasName:          rollbackAliasName,
+		CurrentIndexName:   "index-rollback-300",
+		TargetIndexName:    "index-rollback-200",
+		TargetWriteBlocked: true,
+	})
+
+	assert.Error(t, err)
+	client.AssertNotCalled(t, "UpdateAlias", mock.Anything, mock.Anything)
+}
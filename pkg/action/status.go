@@ -0,0 +1,110 @@
+package action
+
+import (
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+// IndexStatus describes the live state of a configured index.
+type IndexStatus struct {
+	ConfigurationName string `json:"configuration_name"`
+	AliasName         string `json:"alias_name"`
+	// Index is the index targeted by the alias, nil when the alias doesn't exist.
+	Index            *elasticsearch.IndexInfo  `json:"index"`
+	PreviousVersions []elasticsearch.IndexInfo `json:"previous_versions"`
+	// Orphans are newer than the aliased index, they are usually left behind by a failed migration.
+	Orphans []elasticsearch.IndexInfo `json:"orphans"`
+	// PendingAction is what an apply of the local configuration would do.
+	PendingAction strategy.IndexAction `json:"pending_action"`
+	Drift         bool                 `json:"drift"`
+}
+
+type IndexStatusCollection []IndexStatus
+
+type Status struct {
+	client  elasticsearch.Client
+	compare *Compare
+}
+
+func NewStatus(
+	client elasticsearch.Client,
+	indexPrefix string,
+	enableSoftUpdate bool,
+) *Status {
+	return &Status{
+		client:  client,
+		compare: NewCompare(client, indexPrefix, enableSoftUpdate),
+	}
+}
+
+func (s *Status) Status(indexName string, index configuration.Index) (IndexStatus, error) {
+	compareResult, err := s.compare.Compare(indexName, index)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+
+	indexStatus := IndexStatus{
+		ConfigurationName: indexName,
+		AliasName:         compareResult.AliasName,
+		PreviousVersions:  []elasticsearch.IndexInfo{},
+		Orphans:           []elasticsearch.IndexInfo{},
+		PendingAction:     compareResult.Result.Action(),
+		Drift:             compareResult.Drift,
+	}
+
+	versions, err := elasticsearch.ListIndexVersions(s.client, compareResult.AliasName)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+
+	// Without an alias there is no way to know which version is in use.
+	if compareResult.CurrentIndexName == "" {
+		indexStatus.Orphans = versions
+
+		return indexStatus, nil
+	}
+
+	indices, err := s.client.ListIndices(compareResult.CurrentIndexName)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+
+	for _, index := range indices {
+		if index.Name == compareResult.CurrentIndexName {
+			index := index
+			indexStatus.Index = &index
+		}
+	}
+
+	currentPosition := elasticsearch.IndexVersionPosition(versions, compareResult.CurrentIndexName)
+
+	for position, version := range versions {
+		switch {
+		case position == currentPosition:
+			continue
+		case currentPosition >= 0 && position > currentPosition:
+			indexStatus.Orphans = append(indexStatus.Orphans, version)
+		default:
+			indexStatus.PreviousVersions = append(indexStatus.PreviousVersions, version)
+		}
+	}
+
+	return indexStatus, nil
+}
+
+// StatusAll returns the status of every configured index, sorted by configuration name.
+func (s *Status) StatusAll(indexCollection configuration.IndexCollection) (IndexStatusCollection, error) {
+	indexStatusCollection := IndexStatusCollection{}
+
+	for _, indexName := range indexCollection.SortedNames() {
+		indexStatus, err := s.Status(indexName, indexCollection[indexName])
+		if err != nil {
+			return nil, err
+		}
+
+		indexStatusCollection = append(indexStatusCollection, indexStatus)
+	}
+
+	return indexStatusCollection, nil
+}
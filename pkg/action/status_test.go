@@ -0,0 +1,81 @@
+package action_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+func TestStatus_StatusAll(t *testing.T) {
+	aliasName1 := elasticsearch.ResolveAliasName(prefix, indexName1)
+	aliasName2 := elasticsearch.ResolveAliasName(prefix, indexName2)
+	currentIndex := elasticsearch.IndexInfo{
+		Name:      aliasName1 + "-300",
+		Health:    "green",
+		Status:    "open",
+		DocsCount: 12,
+		StoreSize: "4.6kb",
+	}
+
+	client := elasticsearch.NewMockClient()
+
+	client.On("AliasExist", aliasName1).Return(true, nil)
+	client.On("GetAliasedIndex", aliasName1).Return(currentIndex.Name, nil)
+	client.On("GetIndexConfiguration", currentIndex.Name).Return(getConfiguration1(), nil)
+	client.On("ListIndices", aliasName1+"-*").Return(
+		[]elasticsearch.IndexInfo{
+			{Name: aliasName1 + "-100"},
+			{Name: aliasName1 + "-200"},
+			currentIndex,
+			{Name: aliasName1 + "-400"},
+		},
+		nil,
+	)
+	client.On("ListIndices", currentIndex.Name).Return([]elasticsearch.IndexInfo{currentIndex}, nil)
+
+	client.On("AliasExist", aliasName2).Return(false, nil)
+	client.On("ListIndices", aliasName2+"-*").Return([]elasticsearch.IndexInfo{{Name: aliasName2 + "-100"}}, nil)
+
+	indexCollection := configuration.IndexCollection{
+		indexName1: getConfiguration2(),
+		indexName2: getConfiguration1(),
+	}
+
+	indexStatusCollection, err := action.NewStatus(client, prefix, false).StatusAll(indexCollection)
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		action.IndexStatusCollection{
+			{
+				ConfigurationName: indexName2,
+				AliasName:         aliasName2,
+				PreviousVersions:  []elasticsearch.IndexInfo{},
+				Orphans:           []elasticsearch.IndexInfo{{Name: aliasName2 + "-100"}},
+				PendingAction:     strategy.IndexDecisionCreate,
+			},
+			{
+				ConfigurationName: indexName1,
+				AliasName:         aliasName1,
+				Index:             &currentIndex,
+				PreviousVersions: []elasticsearch.IndexInfo{
+					{Name: aliasName1 + "-100"},
+					{Name: aliasName1 + "-200"},
+				},
+				Orphans:       []elasticsearch.IndexInfo{{Name: aliasName1 + "-400"}},
+				PendingAction: strategy.IndexDecisionMigrate,
+			},
+		},
+		indexStatusCollection,
+	)
+
+	encodedStatus, err := json.Marshal(indexStatusCollection[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(encodedStatus), `"index":null`)
+	assert.Contains(t, string(encodedStatus), `"pending_action":"Create"`)
+}
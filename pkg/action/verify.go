@@ -0,0 +1,175 @@
+package action
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+// VerifyOptions configures the checks done on a migrated index before moving the alias.
+// The apply command enables the count check by default, the sample check is opt-in.
+// Without write safety, the writes made during a migration make the indices diverge beyond a zero tolerance.
+type VerifyOptions struct {
+	// CountEnabled compares the number of documents of the indices.
+	CountEnabled bool
+	// Tolerance is the maximum accepted divergence between the indices, in percent.
+	Tolerance float64
+	// SampleSize is the number of documents whose _source is compared, 0 disables the check.
+	SampleSize int
+}
+
+type VerifyResult struct {
+	SourceCount         int64
+	TargetCount         int64
+	SampledDocuments    int
+	MismatchedDocuments []string
+}
+
+// VerificationError is returned when the target index diverges from the source one beyond the tolerance.
+type VerificationError struct {
+	SourceIndexName string
+	TargetIndexName string
+	Result          VerifyResult
+}
+
+func (ve *VerificationError) Error() string {
+	return fmt.Sprintf(
+		"index '%s' diverges from '%s': %d/%d documents, %d/%d sampled documents mismatch %v",
+		ve.TargetIndexName,
+		ve.SourceIndexName,
+		ve.Result.TargetCount,
+		ve.Result.SourceCount,
+		len(ve.Result.MismatchedDocuments),
+		ve.Result.SampledDocuments,
+		ve.Result.MismatchedDocuments,
+	)
+}
+
+type Verify struct {
+	client  elasticsearch.Client
+	options VerifyOptions
+}
+
+func NewVerify(
+	client elasticsearch.Client,
+	options VerifyOptions,
+) *Verify {
+	return &Verify{
+		client:  client,
+		options: options,
+	}
+}
+
+// Verify compares the number of documents, and a sample of them, between two indices.
+func (v *Verify) Verify(sourceIndexName string, targetIndexName string) (VerifyResult, error) {
+	result := VerifyResult{}
+
+	if v.options.CountEnabled {
+		var err error
+
+		if result.SourceCount, err = v.count(sourceIndexName); err != nil {
+			return result, err
+		}
+
+		if result.TargetCount, err = v.count(targetIndexName); err != nil {
+			return result, err
+		}
+	}
+
+	if v.options.SampleSize > 0 {
+		if err := v.compareSample(sourceIndexName, targetIndexName, &result); err != nil {
+			return result, err
+		}
+	}
+
+	if !v.isWithinTolerance(result) {
+		return result, &VerificationError{
+			SourceIndexName: sourceIndexName,
+			TargetIndexName: targetIndexName,
+			Result:          result,
+		}
+	}
+
+	return result, nil
+}
+
+func (v *Verify) count(indexName string) (int64, error) {
+	if err := v.client.RefreshIndex(indexName); err != nil {
+		return 0, err
+	}
+
+	return v.client.CountDocuments(indexName)
+}
+
+func (v *Verify) compareSample(sourceIndexName string, targetIndexName string, result *VerifyResult) error {
+	sourceDocuments, err := v.client.SampleDocuments(sourceIndexName, v.options.SampleSize)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(sourceDocuments))
+	for id := range sourceDocuments {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	targetDocuments, err := v.client.GetDocuments(targetIndexName, ids)
+	if err != nil {
+		return err
+	}
+
+	result.SampledDocuments = len(ids)
+
+	for _, id := range ids {
+		targetDocument, exist := targetDocuments[id]
+		if !exist || hashDocument(sourceDocuments[id]) != hashDocument(targetDocument) {
+			result.MismatchedDocuments = append(result.MismatchedDocuments, id)
+		}
+	}
+
+	return nil
+}
+
+func (v *Verify) isWithinTolerance(result VerifyResult) bool {
+	countDifference := math.Abs(float64(result.SourceCount - result.TargetCount))
+	if divergence(countDifference, float64(result.SourceCount)) > v.options.Tolerance {
+		return false
+	}
+
+	return divergence(
+		float64(len(result.MismatchedDocuments)),
+		float64(result.SampledDocuments),
+	) <= v.options.Tolerance
+}
+
+func divergence(difference float64, total float64) float64 {
+	if difference == 0 {
+		return 0
+	}
+
+	if total == 0 {
+		return math.Inf(1)
+	}
+
+	return difference / total * 100
+}
+
+// hashDocument normalizes the document, so that keys order and formatting are not taken into account.
+func hashDocument(source json.RawMessage) string {
+	var document interface{}
+
+	normalized := []byte(source)
+
+	if err := json.Unmarshal(source, &document); err == nil {
+		if encoded, err := json.Marshal(document); err == nil {
+			normalized = encoded
+		}
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(normalized))
+}
@@ -0,0 +1,147 @@
+package action_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+const verifySourceIndexName = "index-source"
+const verifyTargetIndexName = "index-target"
+
+func getVerifyMockClient(sourceCount int64, targetCount int64) *elasticsearch.MockClient {
+	client := elasticsearch.NewMockClient()
+	client.On("RefreshIndex", verifySourceIndexName).Return(nil)
+	client.On("RefreshIndex", verifyTargetIndexName).Return(nil)
+	client.On("CountDocuments", verifySourceIndexName).Return(sourceCount, nil)
+	client.On("CountDocuments", verifyTargetIndexName).Return(targetCount, nil)
+
+	return client
+}
+
+func TestVerify_Verify_Disabled(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+
+	_, err := action.NewVerify(client, action.VerifyOptions{}).Verify(verifySourceIndexName, verifyTargetIndexName)
+	assert.NoError(t, err)
+
+	client.AssertNotCalled(t, "CountDocuments", verifySourceIndexName)
+}
+
+func TestVerify_Verify_SampleWithoutCount(t *testing.T) {
+	documents := elasticsearch.Documents{"1": json.RawMessage(`{"id":1}`)}
+
+	client := elasticsearch.NewMockClient()
+	client.On("SampleDocuments", verifySourceIndexName, 10).Return(documents, nil)
+	client.On("GetDocuments", verifyTargetIndexName, []string{"1"}).Return(documents, nil)
+
+	result, err := action.NewVerify(client, action.VerifyOptions{
+		SampleSize: 10,
+	}).Verify(verifySourceIndexName, verifyTargetIndexName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.SampledDocuments)
+
+	client.AssertNotCalled(t, "CountDocuments", verifySourceIndexName)
+}
+
+func TestVerify_Verify_Count(t *testing.T) {
+	testCases := []struct {
+		name        string
+		sourceCount int64
+		targetCount int64
+		tolerance   float64
+		expectError bool
+	}{
+		{name: "same count", sourceCount: 100, targetCount: 100, tolerance: 0, expectError: false},
+		{name: "empty indices", sourceCount: 0, targetCount: 0, tolerance: 0, expectError: false},
+		{name: "missing documents", sourceCount: 100, targetCount: 99, tolerance: 0, expectError: true},
+		{name: "within tolerance", sourceCount: 100, targetCount: 99, tolerance: 1, expectError: false},
+		{name: "beyond tolerance", sourceCount: 100, targetCount: 97, tolerance: 2.5, expectError: true},
+		{name: "empty source", sourceCount: 0, targetCount: 1, tolerance: 50, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := getVerifyMockClient(tc.sourceCount, tc.targetCount)
+
+			result, err := action.NewVerify(client, action.VerifyOptions{
+				CountEnabled: true,
+				Tolerance:    tc.tolerance,
+			}).Verify(verifySourceIndexName, verifyTargetIndexName)
+
+			assert.Equal(t, tc.sourceCount, result.SourceCount)
+			assert.Equal(t, tc.targetCount, result.TargetCount)
+
+			if tc.expectError {
+				assert.IsType(t, &action.VerificationError{}, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVerify_Verify_Sample(t *testing.T) {
+	sourceDocuments := elasticsearch.Documents{
+		"1": json.RawMessage(`{"name":"a","id":1}`),
+		"2": json.RawMessage(`{"name":"b","id":2}`),
+	}
+
+	testCases := []struct {
+		name               string
+		targetDocuments    elasticsearch.Documents
+		expectedMismatches []string
+		expectError        bool
+	}{
+		{
+			name: "same documents",
+			targetDocuments: elasticsearch.Documents{
+				"1": json.RawMessage(`{"id": 1, "name": "a"}`),
+				"2": json.RawMessage(`{"name":"b","id":2}`),
+			},
+			expectedMismatches: nil,
+			expectError:        false,
+		},
+		{
+			name: "different and missing documents",
+			targetDocuments: elasticsearch.Documents{
+				"1": json.RawMessage(`{"name":"changed","id":1}`),
+			},
+			expectedMismatches: []string{"1", "2"},
+			expectError:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := getVerifyMockClient(2, 2)
+			client.On("SampleDocuments", verifySourceIndexName, 10).Return(sourceDocuments, nil)
+			client.On("GetDocuments", verifyTargetIndexName, []string{"1", "2"}).Return(tc.targetDocuments, nil)
+
+			result, err := action.NewVerify(client, action.VerifyOptions{
+				CountEnabled: true,
+				SampleSize:   10,
+			}).Verify(verifySourceIndexName, verifyTargetIndexName)
+
+			assert.Equal(t, 2, result.SampledDocuments)
+			assert.Equal(t, tc.expectedMismatches, result.MismatchedDocuments)
+
+			if tc.expectError {
+				assert.IsType(t, &action.VerificationError{}, err)
+				assert.Contains(t, err.Error(), "2/2 sampled documents mismatch")
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package action
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+)
+
+// WriteSafetyMode defines how the writes made on the source index during a migration are preserved.
+type WriteSafetyMode int
+
+const (
+	// WriteSafetyNone doesn't protect the writes, it's only safe for read-only indices.
+	WriteSafetyNone WriteSafetyMode = iota
+	// WriteSafetyBlockWrites blocks the writes on the source index during the whole migration.
+	WriteSafetyBlockWrites
+	// WriteSafetyCatchUp blocks the writes only while the documents changed during the reindex are copied again.
+	WriteSafetyCatchUp
+)
+
+func (m WriteSafetyMode) String() string {
+	return writeSafetyModeNames()[m]
+}
+
+func writeSafetyModeNames() []string {
+	return []string{"none", "block-writes", "catch-up"}
+}
+
+func NewWriteSafetyModeFromString(mode string) (WriteSafetyMode, error) {
+	for i, name := range writeSafetyModeNames() {
+		if name == mode {
+			return WriteSafetyMode(i), nil
+		}
+	}
+
+	return WriteSafetyNone, fmt.Errorf("unknown write safety mode '%s'", mode)
+}
+
+// WriteSafetyOptions configures how the writes are preserved during a migration.
+type WriteSafetyOptions struct {
+	Mode WriteSafetyMode
+	// TimestampField is the date field used to find the documents changed during the reindex.
+	TimestampField string
+	// CatchUpMargin is subtracted from the migration start to absorb clock differences.
+	CatchUpMargin time.Duration
+}
+
+// isWriteBlocked tells if index.blocks.write is set, the settings may be nested or flat.
+func isWriteBlocked(index configuration.Index) bool {
+	indexSettings := index.GetSettings().GetIndexSettings()
+
+	writeBlock, exist := indexSettings["blocks.write"]
+	if !exist {
+		blocks, _ := indexSettings["blocks"].(map[string]interface{})
+		writeBlock = blocks["write"]
+	}
+
+	return fmt.Sprint(writeBlock) == "true"
+}
@@ -0,0 +1,226 @@
+package action_test
+
+import (
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchy/stretchy/pkg/action"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+	"github.com/stretchy/stretchy/pkg/strategy"
+)
+
+const catchUpTaskID = "node:5678"
+
+func getWriteSafetyCompareResult() action.CompareResult {
+	return action.CompareResult{
+		AliasName:        migrateAliasName,
+		CurrentIndexName: currentMigrateIndexName,
+		CurrentConfig: configuration.New(
+			configuration.Mappings{
+				"properties": map[string]interface{}{
+					"updated_at": map[string]interface{}{"type": "date"},
+				},
+			},
+			configuration.Settings{},
+		),
+		NewConfig: migrateConfig(),
+		Result:    strategy.NewIndexVoterResult(strategy.IndexDecisionMigrate, nil),
+	}
+}
+
+func TestNewWriteSafetyModeFromString(t *testing.T) {
+	for _, mode := range []action.WriteSafetyMode{
+		action.WriteSafetyNone,
+		action.WriteSafetyBlockWrites,
+		action.WriteSafetyCatchUp,
+	} {
+		parsedMode, err := action.NewWriteSafetyModeFromString(mode.String())
+		assert.NoError(t, err)
+		assert.Equal(t, mode, parsedMode)
+	}
+
+	_, err := action.NewWriteSafetyModeFromString("unknown")
+	assert.Error(t, err)
+}
+
+func TestApply_Apply_MigrateBlockWrites(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	now := time.Now()
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	newIndexName := elasticsearch.CreateIndexName(migrateAliasName)
+	calls := []string{}
+	record := func(call string) func(mock.Arguments) {
+		return func(mock.Arguments) { calls = append(calls, call) }
+	}
+
+	client.On("GetIndexConfiguration", currentMigrateIndexName).Return(getWriteSafetyCompareResult().CurrentConfig, nil)
+	client.On("CreateIndex", newIndexName, withMetadata(t, migrateConfig())).Return(nil)
+	client.On("SetWriteBlock", currentMigrateIndexName, true).Return(nil).Run(record("block"))
+	client.On("StartReindex", currentMigrateIndexName, newIndexName).Return(reindexTaskID, nil).Run(record("reindex"))
+	client.On("GetReindexTask", reindexTaskID).Return(elasticsearch.ReindexTask{ID: reindexTaskID, Completed: true}, nil)
+	expectLiveHash(t, client, newIndexName, migrateConfig())
+	client.On("UpdateAlias", migrateAliasName, newIndexName).Return(nil).Run(record("alias"))
+	client.On("SetWriteBlock", currentMigrateIndexName, false).Return(nil).Run(record("unblock"))
+
+	err := action.NewApply(client, action.ApplyOptions{
+		WriteSafety: action.WriteSafetyOptions{Mode: action.WriteSafetyBlockWrites},
+	}).Apply(getWriteSafetyCompareResult())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"block", "reindex", "alias", "unblock"}, calls)
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestApply_Apply_MigrateCatchUp(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	now := time.Now()
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	newIndexName := elasticsearch.CreateIndexName(migrateAliasName)
+	calls := []string{}
+	record := func(call string) func(mock.Arguments) {
+		return func(mock.Arguments) { calls = append(calls, call) }
+	}
+
+	client.On("GetIndexConfiguration", currentMigrateIndexName).Return(getWriteSafetyCompareResult().CurrentConfig, nil)
+	client.On("CreateIndex", newIndexName, withMetadata(t, migrateConfig())).Return(nil)
+	client.On("StartReindex", currentMigrateIndexName, newIndexName).Return(reindexTaskID, nil).Run(record("reindex"))
+	client.On("GetReindexTask", reindexTaskID).Return(elasticsearch.ReindexTask{ID: reindexTaskID, Completed: true}, nil)
+	client.On("SetWriteBlock", currentMigrateIndexName, true).Return(nil).Run(record("block"))
+	client.On(
+		"StartCatchUpReindex",
+		currentMigrateIndexName,
+		newIndexName,
+		"updated_at",
+		now.Add(-time.Minute),
+	).Return(catchUpTaskID, nil).Run(record("catch-up"))
+	client.On("GetReindexTask", catchUpTaskID).Return(elasticsearch.ReindexTask{ID: catchUpTaskID, Completed: true}, nil)
+	expectLiveHash(t, client, newIndexName, migrateConfig())
+	client.On("UpdateAlias", migrateAliasName, newIndexName).Return(nil).Run(record("alias"))
+	client.On("SetWriteBlock", currentMigrateIndexName, false).Return(nil).Run(record("unblock"))
+
+	err := action.NewApply(client, action.ApplyOptions{
+		WriteSafety: action.WriteSafetyOptions{
+			Mode:           action.WriteSafetyCatchUp,
+			TimestampField: "updated_at",
+			CatchUpMargin:  time.Minute,
+		},
+	}).Apply(getWriteSafetyCompareResult())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"reindex", "block", "catch-up", "alias", "unblock"}, calls)
+	mock.AssertExpectationsForObjects(t, client)
+}
+
+func TestApply_Apply_MigrateCatchUp_UnblocksOnFailure(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	now := time.Now()
+
+	patch := monkey.Patch(time.Now, func() time.Time { return now })
+	defer patch.Unpatch()
+
+	newIndexName := elasticsearch.CreateIndexName(migrateAliasName)
+
+	client.On("GetIndexConfiguration", currentMigrateIndexName).Return(getWriteSafetyCompareResult().CurrentConfig, nil)
+	client.On("CreateIndex", newIndexName, withMetadata(t, migrateConfig())).Return(nil)
+	client.On("StartReindex", currentMigrateIndexName, newIndexName).Return(reindexTaskID, nil)
+	client.On("GetReindexTask", reindexTaskID).Return(elasticsearch.ReindexTask{ID: reindexTaskID, Completed: true}, nil)
+	client.On("SetWriteBlock", currentMigrateIndexName, true).Return(nil)
+	client.On(
+		"StartCatchUpReindex",
+		currentMigrateIndexName,
+		newIndexName,
+		"updated_at",
+		now,
+	).Return(catchUpTaskID, nil)
+	client.On("GetReindexTask", catchUpTaskID).Return(
+		elasticsearch.ReindexTask{ID: catchUpTaskID, Completed: true, Error: "search_phase_execution_exception"},
+		nil,
+	)
+	client.On("SetWriteBlock", currentMigrateIndexName, false).Return(nil)
+
+	err := action.NewApply(client, action.ApplyOptions{
+		WriteSafety: action.WriteSafetyOptions{
+			Mode:           action.WriteSafetyCatchUp,
+			TimestampField: "updated_at",
+		},
+	}).Apply(getWriteSafetyCompareResult())
+
+	assert.Error(t, err)
+	mock.AssertExpectationsForObjects(t, client)
+	client.AssertNotCalled(t, "UpdateAlias", migrateAliasName, newIndexName)
+}
+
+func TestApply_Apply_MigrateBlockWrites_KeepsExistingBlock(t *testing.T) {
+	testCases := []struct {
+		name     string
+		settings configuration.Settings
+	}{
+		{
+			name:     "nested settings",
+			settings: configuration.Settings{"index": map[string]interface{}{"blocks": map[string]interface{}{"write": "true"}}},
+		},
+		{
+			name:     "flat settings",
+			settings: configuration.Settings{"index": map[string]interface{}{"blocks.write": true}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := elasticsearch.NewMockClient()
+			now := time.Now()
+
+			patch := monkey.Patch(time.Now, func() time.Time { return now })
+			defer patch.Unpatch()
+
+			newIndexName := elasticsearch.CreateIndexName(migrateAliasName)
+
+			client.On("GetIndexConfiguration", currentMigrateIndexName).Return(
+				configuration.New(configuration.Mappings{}, tc.settings),
+				nil,
+			)
+			client.On("CreateIndex", newIndexName, withMetadata(t, migrateConfig())).Return(nil)
+			client.On("SetWriteBlock", currentMigrateIndexName, true).Return(nil)
+			client.On("StartReindex", currentMigrateIndexName, newIndexName).Return(reindexTaskID, nil)
+			client.On("GetReindexTask", reindexTaskID).Return(
+				elasticsearch.ReindexTask{ID: reindexTaskID, Completed: true},
+				nil,
+			)
+			expectLiveHash(t, client, newIndexName, migrateConfig())
+			client.On("UpdateAlias", migrateAliasName, newIndexName).Return(nil)
+
+			err := action.NewApply(client, action.ApplyOptions{
+				WriteSafety: action.WriteSafetyOptions{Mode: action.WriteSafetyBlockWrites},
+			}).Apply(getWriteSafetyCompareResult())
+
+			assert.NoError(t, err)
+			mock.AssertExpectationsForObjects(t, client)
+			client.AssertNotCalled(t, "SetWriteBlock", currentMigrateIndexName, false)
+		})
+	}
+}
+
+func TestApply_Apply_MigrateCatchUp_MissingTimestampField(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+
+	err := action.NewApply(client, action.ApplyOptions{
+		WriteSafety: action.WriteSafetyOptions{
+			Mode:           action.WriteSafetyCatchUp,
+			TimestampField: "not_mapped",
+		},
+	}).Apply(getWriteSafetyCompareResult())
+
+	assert.Error(t, err)
+	client.AssertNotCalled(t, "CreateIndex", mock.Anything, mock.Anything)
+}
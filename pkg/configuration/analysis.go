@@ -0,0 +1,141 @@
+package configuration
+
+// AnalysisComponent is an analyzer, a normalizer, a tokenizer, a filter or a char_filter of the analysis settings.
+type AnalysisComponent struct {
+	Kind string
+	Name string
+}
+
+// analyzerMappingParameters are the mapping parameters referencing an analyzer.
+func analyzerMappingParameters() []string {
+	return []string{"analyzer", "search_analyzer", "search_quote_analyzer"}
+}
+
+// analyzerDependencyKinds are the components an analyzer, or a normalizer, is built with.
+func analyzerDependencyKinds() []string {
+	return []string{"tokenizer", "filter", "char_filter"}
+}
+
+// IsAnalysisChange tells if the change targets the analysis settings.
+func (c Change) IsAnalysisChange() bool {
+	return len(c.Path) >= 3 && c.Path[0] == "settings" && c.Path[1] == "index" && c.Path[2] == "analysis"
+}
+
+// AnalysisComponents returns the analysis components touched by the change.
+func (c Change) AnalysisComponents() []AnalysisComponent {
+	if !c.IsAnalysisChange() {
+		return nil
+	}
+
+	value := c.To
+	if value == nil {
+		value = c.From
+	}
+
+	switch len(c.Path) {
+	case 3:
+		components := []AnalysisComponent{}
+
+		analysis, _ := value.(map[string]interface{})
+		for kind, definitions := range analysis {
+			components = append(components, analysisComponents(kind, definitions)...)
+		}
+
+		return components
+	case 4:
+		return analysisComponents(c.Path[3], value)
+	}
+
+	return []AnalysisComponent{{Kind: c.Path[3], Name: c.Path[4]}}
+}
+
+func analysisComponents(kind string, definitions interface{}) []AnalysisComponent {
+	components := []AnalysisComponent{}
+
+	definitionsMap, _ := definitions.(map[string]interface{})
+	for name := range definitionsMap {
+		components = append(components, AnalysisComponent{Kind: kind, Name: name})
+	}
+
+	return components
+}
+
+// UsedAnalysisComponents returns the analysis components the mappings rely on,
+// either directly or through an analyzer or a normalizer.
+func (i Index) UsedAnalysisComponents() map[AnalysisComponent]bool {
+	// The default analyzers are used by every text field without an explicit analyzer.
+	used := map[AnalysisComponent]bool{
+		{Kind: "analyzer", Name: "default"}:        true,
+		{Kind: "analyzer", Name: "default_search"}: true,
+	}
+
+	collectMappingsAnalysis(map[string]interface{}(i.Mappings), used)
+
+	analysis, _ := i.Settings.GetIndexSettings()["analysis"].(map[string]interface{})
+
+	for _, kind := range []string{"analyzer", "normalizer"} {
+		definitions, _ := analysis[kind].(map[string]interface{})
+
+		for name, definition := range definitions {
+			if !used[AnalysisComponent{Kind: kind, Name: name}] {
+				continue
+			}
+
+			definitionMap, _ := definition.(map[string]interface{})
+
+			for _, dependencyKind := range analyzerDependencyKinds() {
+				for _, dependency := range analysisNames(definitionMap[dependencyKind]) {
+					used[AnalysisComponent{Kind: dependencyKind, Name: dependency}] = true
+				}
+			}
+		}
+	}
+
+	return used
+}
+
+// collectMappingsAnalysis walks the whole mappings, so that multi-fields and dynamic templates are included.
+func collectMappingsAnalysis(value interface{}, used map[AnalysisComponent]bool) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, subValue := range typedValue {
+			name, isAString := subValue.(string)
+
+			switch {
+			case isAString && key == "normalizer":
+				used[AnalysisComponent{Kind: "normalizer", Name: name}] = true
+			case isAString:
+				for _, parameter := range analyzerMappingParameters() {
+					if key == parameter {
+						used[AnalysisComponent{Kind: "analyzer", Name: name}] = true
+					}
+				}
+			default:
+				collectMappingsAnalysis(subValue, used)
+			}
+		}
+	case []interface{}:
+		for _, subValue := range typedValue {
+			collectMappingsAnalysis(subValue, used)
+		}
+	}
+}
+
+func analysisNames(value interface{}) []string {
+	switch typedValue := value.(type) {
+	case string:
+		return []string{typedValue}
+	case []interface{}:
+		names := []string{}
+
+		for _, name := range typedValue {
+			if stringName, isAString := name.(string); isAString {
+				names = append(names, stringName)
+			}
+		}
+
+		return names
+	}
+
+	return nil
+}
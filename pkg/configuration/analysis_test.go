@@ -0,0 +1,132 @@
+package configuration_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/configuration"
+)
+
+func TestIndex_UsedAnalysisComponents(t *testing.T) {
+	index := configuration.New(
+		configuration.Mappings{
+			"dynamic_templates": []interface{}{
+				map[string]interface{}{
+					"strings": map[string]interface{}{
+						"mapping": map[string]interface{}{
+							"type":       "keyword",
+							"normalizer": "lowercase_normalizer",
+						},
+					},
+				},
+			},
+			"properties": map[string]interface{}{
+				"title": map[string]interface{}{
+					"type":            "text",
+					"analyzer":        "title_analyzer",
+					"search_analyzer": "standard",
+					"fields": map[string]interface{}{
+						"folded": map[string]interface{}{
+							"type":     "text",
+							"analyzer": "folding_analyzer",
+						},
+					},
+				},
+				"analyzer": map[string]interface{}{
+					"type": "keyword",
+				},
+			},
+		},
+		configuration.Settings{
+			"analysis": map[string]interface{}{
+				"analyzer": map[string]interface{}{
+					"title_analyzer": map[string]interface{}{
+						"tokenizer":   "title_tokenizer",
+						"filter":      []interface{}{"lowercase", "title_filter"},
+						"char_filter": []interface{}{"html_strip"},
+					},
+					"unused_analyzer": map[string]interface{}{
+						"tokenizer": "unused_tokenizer",
+					},
+				},
+				"normalizer": map[string]interface{}{
+					"lowercase_normalizer": map[string]interface{}{
+						"filter": []interface{}{"lowercase"},
+					},
+				},
+			},
+		},
+	)
+
+	assert.Equal(
+		t,
+		map[configuration.AnalysisComponent]bool{
+			{Kind: "analyzer", Name: "default"}:                true,
+			{Kind: "analyzer", Name: "default_search"}:         true,
+			{Kind: "analyzer", Name: "title_analyzer"}:         true,
+			{Kind: "analyzer", Name: "standard"}:               true,
+			{Kind: "analyzer", Name: "folding_analyzer"}:       true,
+			{Kind: "normalizer", Name: "lowercase_normalizer"}: true,
+			{Kind: "tokenizer", Name: "title_tokenizer"}:       true,
+			{Kind: "filter", Name: "lowercase"}:                true,
+			{Kind: "filter", Name: "title_filter"}:             true,
+			{Kind: "char_filter", Name: "html_strip"}:          true,
+		},
+		index.UsedAnalysisComponents(),
+	)
+}
+
+func TestChange_AnalysisComponents(t *testing.T) {
+	testCases := []struct {
+		name       string
+		change     configuration.Change
+		components []configuration.AnalysisComponent
+	}{
+		{
+			name: "whole analysis",
+			change: configuration.Change{
+				Path: []string{"settings", "index", "analysis"},
+				To: map[string]interface{}{
+					"filter": map[string]interface{}{
+						"my_filter": map[string]interface{}{"type": "stop"},
+					},
+				},
+			},
+			components: []configuration.AnalysisComponent{{Kind: "filter", Name: "my_filter"}},
+		},
+		{
+			name: "analysis kind",
+			change: configuration.Change{
+				Path: []string{"settings", "index", "analysis", "tokenizer"},
+				To: map[string]interface{}{
+					"my_tokenizer": map[string]interface{}{"type": "ngram"},
+				},
+			},
+			components: []configuration.AnalysisComponent{{Kind: "tokenizer", Name: "my_tokenizer"}},
+		},
+		{
+			name: "analysis component property",
+			change: configuration.Change{
+				Path: []string{"settings", "index", "analysis", "analyzer", "my_analyzer", "tokenizer"},
+				From: "standard",
+				To:   "whitespace",
+			},
+			components: []configuration.AnalysisComponent{{Kind: "analyzer", Name: "my_analyzer"}},
+		},
+		{
+			name: "not an analysis change",
+			change: configuration.Change{
+				Path: []string{"settings", "index", "number_of_replicas"},
+				To:   1,
+			},
+			components: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.components, tc.change.AnalysisComponents())
+		})
+	}
+}
@@ -17,7 +17,36 @@ const (
 )
 
 func (d ChangeType) String() string {
-	return [...]string{"CREATE", "UPDATE", "DELETE"}[d]
+	return changeTypeNames()[d]
+}
+
+func changeTypeNames() []string {
+	return []string{"CREATE", "UPDATE", "DELETE"}
+}
+
+func NewChangeTypeFromString(changeType string) (ChangeType, error) {
+	for i, name := range changeTypeNames() {
+		if name == changeType {
+			return ChangeType(i), nil
+		}
+	}
+
+	return ChangeTypeCreate, fmt.Errorf("unknown change type '%s'", changeType)
+}
+
+func (d ChangeType) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *ChangeType) UnmarshalText(text []byte) error {
+	changeType, err := NewChangeTypeFromString(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = changeType
+
+	return nil
 }
 
 func NewChangeTypeFromDiffType(diffType string) ChangeType {
@@ -34,10 +63,10 @@ func NewChangeTypeFromDiffType(diffType string) ChangeType {
 }
 
 type Change struct {
-	Type ChangeType
-	Path []string
-	From interface{}
-	To   interface{}
+	Type ChangeType  `json:"type" yaml:"type"`
+	Path []string    `json:"path" yaml:"path"`
+	From interface{} `json:"from" yaml:"from"`
+	To   interface{} `json:"to" yaml:"to"`
 }
 
 func (c Change) FullPath() string {
@@ -87,3 +116,67 @@ func (c Change) ShouldBeReported() bool {
 }
 
 type ChangeCollection []Change
+
+// IsDynamicSetting tells if the change only touches index settings that can be updated on a live index.
+func (c Change) IsDynamicSetting() bool {
+	if len(c.Path) < 3 || c.Path[0] != "settings" || c.Path[1] != "index" {
+		return false
+	}
+
+	value := c.To
+	if value == nil {
+		value = c.From
+	}
+
+	for _, setting := range settingKeys(strings.Join(c.Path[2:], "."), value) {
+		if !IsDynamicIndexSetting(setting) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// settingKeys returns the dot notation keys of every leaf setting of a value.
+func settingKeys(key string, value interface{}) []string {
+	values, isAMap := value.(map[string]interface{})
+	if !isAMap || len(values) == 0 {
+		return []string{key}
+	}
+
+	keys := []string{}
+
+	for subKey, subValue := range values {
+		keys = append(keys, settingKeys(key+"."+subKey, subValue)...)
+	}
+
+	return keys
+}
+
+// SettingsChanges returns the settings to send to the cluster to apply the changes of the collection.
+// Deleted settings are set to null, so that they are reset to their default value.
+func (cc ChangeCollection) SettingsChanges() Settings {
+	settings := Settings{}
+
+	for _, c := range cc {
+		if len(c.Path) < 2 || c.Path[0] != "settings" {
+			continue
+		}
+
+		parent := map[string]interface{}(settings)
+
+		for _, key := range c.Path[1 : len(c.Path)-1] {
+			child, exist := parent[key].(map[string]interface{})
+			if !exist {
+				child = map[string]interface{}{}
+				parent[key] = child
+			}
+
+			parent = child
+		}
+
+		parent[c.Path[len(c.Path)-1]] = c.To
+	}
+
+	return settings
+}
@@ -276,3 +276,141 @@ func TestNewChangeTypeFromDiffType_Unknown(t *testing.T) {
 		},
 	)
 }
+
+func TestNewChangeTypeFromString(t *testing.T) {
+	for _, changeType := range []configuration.ChangeType{
+		configuration.ChangeTypeCreate,
+		configuration.ChangeTypeUpdate,
+		configuration.ChangeTypeDelete,
+	} {
+		changeType := changeType
+		t.Run(changeType.String(), func(t *testing.T) {
+			parsedChangeType, err := configuration.NewChangeTypeFromString(changeType.String())
+			assert.NoError(t, err)
+			assert.Equal(t, changeType, parsedChangeType)
+		})
+	}
+
+	_, err := configuration.NewChangeTypeFromString("UNKNOWN")
+	assert.Error(t, err)
+}
+
+func TestChange_IsDynamicSetting(t *testing.T) {
+	testCases := []struct {
+		name             string
+		change           configuration.Change
+		isDynamicSetting bool
+	}{
+		{
+			name: "number_of_replicas",
+			change: configuration.Change{
+				Path: []string{"settings", "index", "number_of_replicas"},
+				To:   2,
+			},
+			isDynamicSetting: true,
+		},
+		{
+			name: "nested dynamic setting",
+			change: configuration.Change{
+				Path: []string{"settings", "index", "search", "idle", "after"},
+				To:   "30s",
+			},
+			isDynamicSetting: true,
+		},
+		{
+			name: "dynamic settings group",
+			change: configuration.Change{
+				Path: []string{"settings", "index", "blocks"},
+				To: map[string]interface{}{
+					"write":     true,
+					"read_only": false,
+				},
+			},
+			isDynamicSetting: true,
+		},
+		{
+			name: "removed dynamic setting",
+			change: configuration.Change{
+				Type: configuration.ChangeTypeDelete,
+				Path: []string{"settings", "index", "refresh_interval"},
+				From: "1s",
+			},
+			isDynamicSetting: true,
+		},
+		{
+			name: "number_of_shards",
+			change: configuration.Change{
+				Path: []string{"settings", "index", "number_of_shards"},
+				To:   5,
+			},
+			isDynamicSetting: false,
+		},
+		{
+			name: "analysis",
+			change: configuration.Change{
+				Path: []string{"settings", "index", "analysis"},
+				To: map[string]interface{}{
+					"analyzer": map[string]interface{}{},
+				},
+			},
+			isDynamicSetting: false,
+		},
+		{
+			name: "mappings",
+			change: configuration.Change{
+				Path: []string{"mappings", "properties", "field"},
+			},
+			isDynamicSetting: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.isDynamicSetting, tc.change.IsDynamicSetting())
+		})
+	}
+}
+
+func TestChangeCollection_SettingsChanges(t *testing.T) {
+	changes := configuration.ChangeCollection{
+		{
+			Type: configuration.ChangeTypeUpdate,
+			Path: []string{"settings", "index", "number_of_replicas"},
+			To:   2,
+		},
+		{
+			Type: configuration.ChangeTypeCreate,
+			Path: []string{"settings", "index", "blocks", "write"},
+			To:   true,
+		},
+		{
+			Type: configuration.ChangeTypeDelete,
+			Path: []string{"settings", "index", "refresh_interval"},
+			From: "1s",
+		},
+		{
+			Type: configuration.ChangeTypeCreate,
+			Path: []string{"mappings", "properties", "field"},
+			To:   map[string]interface{}{"type": "keyword"},
+		},
+		{
+			Type: configuration.ChangeTypeUpdate,
+			Path: []string{},
+		},
+	}
+
+	assert.Equal(
+		t,
+		configuration.Settings{
+			"index": map[string]interface{}{
+				"number_of_replicas": 2,
+				"blocks": map[string]interface{}{
+					"write": true,
+				},
+				"refresh_interval": nil,
+			},
+		},
+		changes.SettingsChanges(),
+	)
+}
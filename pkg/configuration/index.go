@@ -2,6 +2,8 @@ package configuration
 
 import (
 	"encoding/json"
+
+	"github.com/r3labs/diff"
 	"gopkg.in/yaml.v3"
 )
 
@@ -81,3 +83,76 @@ func (i Index) Diff(mapping Index) (ChangeCollection, error) {
 
 	return changes, nil
 }
+
+// WithUnreportedChanges returns a copy of the index where the differences with the current index that are not
+// reported as changes, e.g. the default ignore_above added by the cluster, take the current values.
+func (i Index) WithUnreportedChanges(current Index) (Index, error) {
+	settings, err := withUnreportedChanges("settings", current.Settings, i.Settings)
+	if err != nil {
+		return Index{}, err
+	}
+
+	mappings, err := withUnreportedChanges("mappings", current.Mappings, i.Mappings)
+	if err != nil {
+		return Index{}, err
+	}
+
+	return Index{
+		Mappings: mappings,
+		Settings: settings,
+	}, nil
+}
+
+func withUnreportedChanges(
+	section string,
+	current map[string]interface{},
+	values map[string]interface{},
+) (map[string]interface{}, error) {
+	changeLogs, err := diff.Diff(current, values)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range changeLogs {
+		change := Change{
+			Type: NewChangeTypeFromDiffType(c.Type),
+			Path: append([]string{section}, c.Path...),
+			From: c.From,
+			To:   c.To,
+		}
+
+		if !change.ShouldBeReported() {
+			values = setValue(values, c.Path, c.From)
+		}
+	}
+
+	return values, nil
+}
+
+// setValue returns a copy of the values with the value set at the path, the maps along the path are copied.
+// The values are returned untouched when a parent of the path doesn't exist.
+func setValue(values map[string]interface{}, path []string, value interface{}) map[string]interface{} {
+	if len(path) == 0 {
+		return values
+	}
+
+	copied := make(map[string]interface{}, len(values)+1)
+	for key, currentValue := range values {
+		copied[key] = currentValue
+	}
+
+	if len(path) == 1 {
+		copied[path[0]] = value
+
+		return copied
+	}
+
+	child, isAMap := values[path[0]].(map[string]interface{})
+	if !isAMap {
+		return values
+	}
+
+	copied[path[0]] = setValue(child, path[1:], value)
+
+	return copied
+}
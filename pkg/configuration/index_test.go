@@ -275,3 +275,41 @@ mappings:
 		index,
 	)
 }
+
+func TestIndex_WithUnreportedChanges(t *testing.T) {
+	currentConfiguration := createConfiguration(
+		map[string]interface{}{
+			"properties": map[string]interface{}{
+				"name":  map[string]interface{}{"type": "keyword", "ignore_above": float64(256)},
+				"price": map[string]interface{}{"type": "keyword", "ignore_above": float64(256)},
+			},
+		},
+		map[string]interface{}{},
+	)
+
+	newProperties := map[string]interface{}{
+		"name":  map[string]interface{}{"type": "keyword"},
+		"price": map[string]interface{}{"type": "keyword", "ignore_above": float64(128)},
+	}
+	newConfiguration := createConfiguration(
+		map[string]interface{}{"properties": newProperties},
+		map[string]interface{}{},
+	)
+
+	index, err := newConfiguration.WithUnreportedChanges(currentConfiguration)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		configuration.Mappings{
+			"properties": map[string]interface{}{
+				"name":  map[string]interface{}{"type": "keyword", "ignore_above": float64(256)},
+				"price": map[string]interface{}{"type": "keyword", "ignore_above": float64(128)},
+			},
+		},
+		index.Mappings,
+	)
+
+	// The new configuration is left untouched.
+	assert.Equal(t, map[string]interface{}{"type": "keyword"}, newProperties["name"])
+}
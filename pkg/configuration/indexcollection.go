@@ -1,6 +1,9 @@
 package configuration
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 type IndexCollection map[string]Index
 
@@ -25,3 +28,15 @@ func (mc IndexCollection) Exist(indexName string) bool {
 
 	return exist
 }
+
+// SortedNames returns the names of the indices in alphabetical order.
+func (mc IndexCollection) SortedNames() []string {
+	names := make([]string, 0, len(mc))
+	for name := range mc {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -70,3 +70,14 @@ func TestIndexCollection_Get_NotExist(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, configuration.Index{}, index)
 }
+
+func TestIndexCollection_SortedNames(t *testing.T) {
+	indexCollection := configuration.IndexCollection{
+		"products": getIndexExample(),
+		"orders":   getIndexExample(),
+		"users":    getIndexExample(),
+	}
+
+	assert.Equal(t, []string{"orders", "products", "users"}, indexCollection.SortedNames())
+	assert.Equal(t, []string{}, configuration.NewIndexCollection().SortedNames())
+}
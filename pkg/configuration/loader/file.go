@@ -1,6 +1,7 @@
 package loader
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -67,3 +68,23 @@ func listFilesByExtensions(basePath string, extensions ...string) ([]string, err
 func closeFile(file *os.File) {
 	file.Close()
 }
+
+// saveFile never overwrites an existing file, it could hold templating lost by a load.
+func saveFile(basePath string, name string, extension string, content []byte) error {
+	path := filepath.Join(basePath, name+"."+extension)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("configuration file '%s' already exists", path)
+		}
+
+		return err
+	}
+
+	defer closeFile(f)
+
+	_, err = f.Write(content)
+
+	return err
+}
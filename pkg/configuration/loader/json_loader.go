@@ -43,6 +43,15 @@ func (jl *JSONLoader) LoadAll() (configuration.IndexCollection, error) {
 	return indexCollection, nil
 }
 
+func (jl *JSONLoader) Save(configurationName string, index configuration.Index) error {
+	content, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return saveFile(jl.basePath, configurationName, jl.Supports()[0], append(content, '\n'))
+}
+
 func (jl *JSONLoader) Load(configurationName string) (configuration.Index, error) {
 	indexCollection, err := jl.LoadAll()
 	if err != nil {
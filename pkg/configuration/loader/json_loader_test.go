@@ -1,6 +1,7 @@
 package loader_test
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -110,3 +111,22 @@ func TestJSONLoader_Load_WithSyntaxError(t *testing.T) {
 		mappingCollection,
 	)
 }
+
+func TestJSONLoader_Save(t *testing.T) {
+	basePath := t.TempDir()
+	jsonLoader := loader.NewJSONLoader(basePath)
+
+	err := jsonLoader.Save("live", getLiveIndexExample())
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(basePath, "live.json"))
+
+	index, err := jsonLoader.Load("live")
+	assert.NoError(t, err)
+
+	changes, err := getLiveIndexExample().Diff(index)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	err = jsonLoader.Save("live", getLiveIndexExample())
+	assert.Error(t, err)
+}
@@ -5,5 +5,6 @@ import "github.com/stretchy/stretchy/pkg/configuration"
 type Loader interface {
 	LoadAll() (configuration.IndexCollection, error)
 	Load(configurationName string) (configuration.Index, error)
+	Save(configurationName string, index configuration.Index) error
 	Supports() []string
 }
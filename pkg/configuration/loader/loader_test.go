@@ -2,6 +2,7 @@ package loader_test
 
 import (
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/configuration"
 	"os"
 	"path/filepath"
 	"testing"
@@ -13,3 +14,31 @@ func getScenarioPath(t *testing.T, scenarioName string) string {
 
 	return filepath.Join(currentDir, "test_scenarios", scenarioName)
 }
+
+// getLiveIndexExample returns a configuration typed like the ones read from the cluster.
+func getLiveIndexExample() configuration.Index {
+	return configuration.New(
+		configuration.Mappings{
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":         "keyword",
+					"ignore_above": float64(256),
+				},
+				"price": map[string]interface{}{
+					"type":           "scaled_float",
+					"scaling_factor": float64(100),
+				},
+				"ratio": map[string]interface{}{
+					"type":  "float",
+					"boost": 1.5,
+				},
+			},
+		},
+		configuration.Settings{
+			"index": map[string]interface{}{
+				"number_of_shards":   "1",
+				"number_of_replicas": "0",
+			},
+		},
+	)
+}
@@ -3,6 +3,8 @@ package loader
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -82,6 +84,58 @@ func (yl *YAMLLoader) Load(configurationName string) (configuration.Index, error
 	return indexCollection.Get(configurationName)
 }
 
+func (yl *YAMLLoader) Save(configurationName string, index configuration.Index) error {
+	buf := new(bytes.Buffer)
+
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(map[string]interface{}{
+		"mappings": preserveFloats(map[string]interface{}(index.Mappings)),
+		"settings": preserveFloats(map[string]interface{}(index.Settings)),
+	}); err != nil {
+		return err
+	}
+
+	return saveFile(yl.basePath, configurationName, "yaml", buf.Bytes())
+}
+
+// yamlFloat is written with a decimal part, so that it is loaded back as a float like the values read from the cluster.
+type yamlFloat float64
+
+func (f yamlFloat) MarshalYAML() (interface{}, error) {
+	return &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!float",
+		Value: strconv.FormatFloat(float64(f), 'f', -1, 64) + ".0",
+	}, nil
+}
+
+func preserveFloats(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		values := make(map[string]interface{}, len(typedValue))
+		for key, subValue := range typedValue {
+			values[key] = preserveFloats(subValue)
+		}
+
+		return values
+	case []interface{}:
+		values := make([]interface{}, 0, len(typedValue))
+		for _, subValue := range typedValue {
+			values = append(values, preserveFloats(subValue))
+		}
+
+		return values
+	case float64:
+		if typedValue == math.Trunc(typedValue) && !math.IsInf(typedValue, 0) {
+			return yamlFloat(typedValue)
+		}
+	}
+
+	return value
+}
+
 func (yl *YAMLLoader) loadTemplates() ([]string, error) {
 	templates, err := listFilesByExtensions(yl.basePath, yl.Supports()...)
 	if err != nil {
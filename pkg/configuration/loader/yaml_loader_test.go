@@ -1,6 +1,7 @@
 package loader_test
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -137,3 +138,22 @@ func TestYAMLLoader_Load_WithRecursion(t *testing.T) {
 		mappingCollection,
 	)
 }
+
+func TestYAMLLoader_Save(t *testing.T) {
+	basePath := t.TempDir()
+	yamlLoader := loader.NewYAMLLoader(basePath)
+
+	err := yamlLoader.Save("live", getLiveIndexExample())
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(basePath, "live.yaml"))
+
+	index, err := yamlLoader.Load("live")
+	assert.NoError(t, err)
+
+	changes, err := getLiveIndexExample().Diff(index)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	err = yamlLoader.Save("live", getLiveIndexExample())
+	assert.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package configuration
 
 import (
+	"strings"
+
 	"github.com/imdario/mergo"
 	"github.com/r3labs/diff"
 )
@@ -38,3 +40,24 @@ func (m Mappings) Diff(mappings Mappings) (ChangeCollection, error) {
 
 	return changes, nil
 }
+
+// HasField checks if a field, in dot notation for object and nested fields, is mapped.
+func (m Mappings) HasField(fieldPath string) bool {
+	current := map[string]interface{}(m)
+
+	for _, fieldName := range strings.Split(fieldPath, ".") {
+		properties, ok := current["properties"].(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		field, ok := properties[fieldName].(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		current = field
+	}
+
+	return true
+}
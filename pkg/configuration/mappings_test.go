@@ -100,3 +100,29 @@ func TestMappings_Merge(t *testing.T) {
 		mappings,
 	)
 }
+
+func TestMappings_HasField(t *testing.T) {
+	mappings := configuration.Mappings{
+		"properties": map[string]interface{}{
+			"updated_at": map[string]interface{}{
+				"type": "date",
+			},
+			"author": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"updated_at": map[string]interface{}{
+						"type": "date",
+					},
+				},
+			},
+		},
+	}
+
+	assert.True(t, mappings.HasField("updated_at"))
+	assert.True(t, mappings.HasField("author.updated_at"))
+	assert.True(t, mappings.HasField("author"))
+	assert.False(t, mappings.HasField("created_at"))
+	assert.False(t, mappings.HasField("author.created_at"))
+	assert.False(t, mappings.HasField("updated_at.nested"))
+	assert.False(t, configuration.Mappings{}.HasField("updated_at"))
+}
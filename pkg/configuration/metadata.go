@@ -0,0 +1,113 @@
+package configuration
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const metaKey = "_meta"
+const metadataKey = "stretchy"
+
+// Metadata records how an index has been created by stretchy, it is stored in the _meta of the mappings.
+type Metadata struct {
+	Version           string    `json:"version"`
+	ConfigurationName string    `json:"configuration_name"`
+	ConfigurationHash string    `json:"configuration_hash"`
+	AppliedAt         time.Time `json:"applied_at"`
+	GitSHA            string    `json:"git_sha,omitempty"`
+	// LiveHash is the hash of the configuration read back from the index once applied, with the server defaults.
+	LiveHash string `json:"live_hash,omitempty"`
+}
+
+// Hash returns a content hash of the configuration without its metadata.
+// It doesn't depend on the keys order nor on the numbers type, so YAML and JSON configurations match.
+func (i Index) Hash() (string, error) {
+	index, _ := i.ExtractMetadata()
+
+	content, err := json.Marshal(index)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(content)), nil
+}
+
+// WithMetadata returns a copy of the index whose mappings carry the metadata.
+func (i Index) WithMetadata(metadata Metadata) (Index, error) {
+	encodedMetadata := map[string]interface{}{}
+
+	content, err := json.Marshal(metadata)
+	if err != nil {
+		return Index{}, err
+	}
+
+	if err := json.Unmarshal(content, &encodedMetadata); err != nil {
+		return Index{}, err
+	}
+
+	mappings, meta := i.copyMeta()
+	meta[metadataKey] = encodedMetadata
+	mappings[metaKey] = meta
+
+	return Index{
+		Mappings: mappings,
+		Settings: i.Settings,
+	}, nil
+}
+
+// ExtractMetadata returns a copy of the index without the metadata, and the metadata when the index has some.
+// The other keys of the _meta are left untouched.
+func (i Index) ExtractMetadata() (Index, *Metadata) {
+	currentMeta, _ := i.Mappings[metaKey].(map[string]interface{})
+
+	encodedMetadata, exist := currentMeta[metadataKey]
+	if !exist {
+		return i, nil
+	}
+
+	mappings, meta := i.copyMeta()
+	delete(meta, metadataKey)
+
+	if len(meta) == 0 {
+		delete(mappings, metaKey)
+	} else {
+		mappings[metaKey] = meta
+	}
+
+	index := Index{
+		Mappings: mappings,
+		Settings: i.Settings,
+	}
+
+	metadata := &Metadata{}
+
+	content, err := json.Marshal(encodedMetadata)
+	if err != nil {
+		return index, nil
+	}
+
+	if err := json.Unmarshal(content, metadata); err != nil {
+		return index, nil
+	}
+
+	return index, metadata
+}
+
+// copyMeta copies the mappings and their _meta, so that they can be changed without altering the index.
+func (i Index) copyMeta() (Mappings, map[string]interface{}) {
+	mappings := Mappings{}
+	for key, value := range i.Mappings {
+		mappings[key] = value
+	}
+
+	meta := map[string]interface{}{}
+
+	currentMeta, _ := i.Mappings[metaKey].(map[string]interface{})
+	for key, value := range currentMeta {
+		meta[key] = value
+	}
+
+	return mappings, meta
+}
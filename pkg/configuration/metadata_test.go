@@ -0,0 +1,105 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/configuration"
+)
+
+func getIndexWithMeta() configuration.Index {
+	return configuration.New(
+		configuration.Mappings{
+			"_meta": map[string]interface{}{
+				"owner": "search-team",
+			},
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type": "keyword",
+				},
+			},
+		},
+		configuration.Settings{
+			"number_of_shards": "1",
+		},
+	)
+}
+
+func TestIndex_WithMetadata(t *testing.T) {
+	metadata := configuration.Metadata{
+		Version:           "v1.0.0",
+		ConfigurationName: "products",
+		ConfigurationHash: "abcd",
+		AppliedAt:         time.Date(2020, 5, 15, 10, 0, 0, 0, time.UTC),
+		GitSHA:            "0123456",
+	}
+
+	index, err := getIndexWithMeta().WithMetadata(metadata)
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"owner": "search-team",
+			"stretchy": map[string]interface{}{
+				"version":            "v1.0.0",
+				"configuration_name": "products",
+				"configuration_hash": "abcd",
+				"applied_at":         "2020-05-15T10:00:00Z",
+				"git_sha":            "0123456",
+			},
+		},
+		index.GetMappings()["_meta"],
+	)
+
+	// The original index is left untouched.
+	assert.Equal(t, getIndexWithMeta(), getIndexWithMeta())
+	_, originalMetadata := getIndexWithMeta().ExtractMetadata()
+	assert.Nil(t, originalMetadata)
+
+	extractedIndex, extractedMetadata := index.ExtractMetadata()
+	assert.Equal(t, getIndexWithMeta(), extractedIndex)
+	assert.Equal(t, &metadata, extractedMetadata)
+}
+
+func TestIndex_ExtractMetadata_RemovesEmptyMeta(t *testing.T) {
+	index := configuration.New(configuration.Mappings{}, configuration.Settings{})
+
+	indexWithMetadata, err := index.WithMetadata(configuration.Metadata{ConfigurationName: "products"})
+	assert.NoError(t, err)
+
+	extractedIndex, metadata := indexWithMetadata.ExtractMetadata()
+	assert.Equal(t, index, extractedIndex)
+	assert.Equal(t, "products", metadata.ConfigurationName)
+}
+
+func TestIndex_Hash(t *testing.T) {
+	hash, err := getIndexWithMeta().Hash()
+	assert.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	indexWithMetadata, err := getIndexWithMeta().WithMetadata(configuration.Metadata{ConfigurationHash: hash})
+	assert.NoError(t, err)
+
+	hashWithMetadata, err := indexWithMetadata.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, hash, hashWithMetadata)
+
+	otherIndex := getIndexWithMeta()
+	otherIndex.GetMappings()["dynamic"] = "strict"
+
+	otherHash, err := otherIndex.Hash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, otherHash)
+
+	// Numbers loaded from YAML are integers while the ones read from the cluster are floats.
+	integerIndex := configuration.New(configuration.Mappings{"ignore_above": 256}, configuration.Settings{})
+	floatIndex := configuration.New(configuration.Mappings{"ignore_above": float64(256)}, configuration.Settings{})
+
+	integerHash, err := integerIndex.Hash()
+	assert.NoError(t, err)
+	floatHash, err := floatIndex.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, integerHash, floatHash)
+}
@@ -1,6 +1,8 @@
 package configuration
 
 import (
+	"strings"
+
 	"github.com/imdario/mergo"
 	"github.com/r3labs/diff"
 	"github.com/stretchy/stretchy/pkg/utils"
@@ -86,6 +88,7 @@ func (s Settings) moveSettingsUnderIndexKey() {
 		"gc_deletes",
 		"max_regex_length",
 		"default_pipeline",
+		"analysis",
 	}
 
 	indexSettings := s.GetIndexSettings()
@@ -136,3 +139,72 @@ func (s Settings) Diff(settings Settings) (ChangeCollection, error) {
 
 	return changes, nil
 }
+
+// dynamicIndexSettings are the index settings that can be changed on a live index.
+// The entries ending with a dot are groups where every setting is dynamic.
+func dynamicIndexSettings() []string {
+	return []string{
+		"number_of_replicas",
+		"auto_expand_replicas",
+		"refresh_interval",
+		"search.idle.after",
+		"max_result_window",
+		"max_inner_result_window",
+		"max_rescore_window",
+		"max_docvalue_fields_search",
+		"max_script_fields",
+		"max_ngram_diff",
+		"max_shingle_diff",
+		"max_refresh_listeners",
+		"max_terms_count",
+		"max_regex_length",
+		"max_slices_per_scroll",
+		"analyze.max_token_count",
+		"highlight.max_analyzed_offset",
+		"query.default_field",
+		"routing.allocation.enable",
+		"routing.allocation.total_shards_per_node",
+		"routing.rebalance.enable",
+		"unassigned.node_left.delayed_timeout",
+		"gc_deletes",
+		"default_pipeline",
+		"final_pipeline",
+		"hidden",
+		"priority",
+		"requests.cache.enable",
+		"write.wait_for_active_shards",
+		"translog.durability",
+		"translog.sync_interval",
+		"translog.flush_threshold_size",
+		"merge.scheduler.max_thread_count",
+		"mapping.total_fields.limit",
+		"mapping.depth.limit",
+		"mapping.nested_fields.limit",
+		"mapping.nested_objects.limit",
+		"mapping.field_name_length.limit",
+		"blocks.",
+		"routing.allocation.include.",
+		"routing.allocation.exclude.",
+		"routing.allocation.require.",
+		"merge.policy.",
+		"search.slowlog.",
+		"indexing.slowlog.",
+		"lifecycle.",
+	}
+}
+
+// IsDynamicIndexSetting tells if a setting, given in dot notation under the index key,
+// can be updated without recreating the index.
+func IsDynamicIndexSetting(setting string) bool {
+	for _, dynamicSetting := range dynamicIndexSettings() {
+		if setting == dynamicSetting {
+			return true
+		}
+
+		if strings.HasSuffix(dynamicSetting, ".") && strings.HasPrefix(setting, dynamicSetting) {
+			return true
+		}
+	}
+
+	return false
+}
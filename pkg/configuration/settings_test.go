@@ -250,3 +250,71 @@ func TestSettings_Merge(t *testing.T) {
 		mappings,
 	)
 }
+
+func TestSettings_CleanUp_Analysis(t *testing.T) {
+	getAnalysis := func(filter string) map[string]interface{} {
+		return map[string]interface{}{
+			"analyzer": map[string]interface{}{
+				"folding": map[string]interface{}{
+					"tokenizer": "standard",
+					"filter":    []interface{}{"lowercase", filter},
+				},
+			},
+		}
+	}
+
+	// The analysis of a live index is always returned under the index key.
+	liveIndex := configuration.New(
+		configuration.Mappings{},
+		configuration.Settings{
+			"index": map[string]interface{}{
+				"number_of_shards": "1",
+				"analysis":         getAnalysis("asciifolding"),
+			},
+		},
+	)
+
+	testCases := []struct {
+		name     string
+		settings func(filter string) configuration.Settings
+	}{
+		{
+			name: "analysis under the index key",
+			settings: func(filter string) configuration.Settings {
+				return configuration.Settings{
+					"index": map[string]interface{}{
+						"number_of_shards": "1",
+						"analysis":         getAnalysis(filter),
+					},
+				}
+			},
+		},
+		{
+			name: "top-level analysis",
+			settings: func(filter string) configuration.Settings {
+				return configuration.Settings{
+					"number_of_shards": "1",
+					"analysis":         getAnalysis(filter),
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			unchanged := configuration.New(configuration.Mappings{}, tc.settings("asciifolding"))
+
+			changes, err := liveIndex.Diff(unchanged)
+			assert.NoError(t, err)
+			assert.Empty(t, changes)
+
+			changed := configuration.New(configuration.Mappings{}, tc.settings("elision"))
+
+			changes, err = liveIndex.Diff(changed)
+			assert.NoError(t, err)
+			assert.Len(t, changes, 1)
+			assert.Equal(t, []string{"settings", "index", "analysis", "analyzer", "folding", "filter", "1"}, changes[0].Path)
+		})
+	}
+}
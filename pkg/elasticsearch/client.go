@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/stretchy/stretchy/pkg/configuration"
@@ -17,20 +18,56 @@ type Client interface {
 	AliasExist(aliasName string) (bool, error)
 
 	CreateIndex(indexName string, mapping configuration.Index) error
+	DeleteIndex(indexName string) error
 	CreateAlias(aliasName string, indexName string) error
 
 	UpdateAlias(aliasName string, newIndexName string) error
 
 	GetAliasedIndex(aliasName string) (string, error)
+	ListIndices(pattern string) ([]IndexInfo, error)
+	ListAliases(pattern string) (map[string]string, error)
+	GetIndexAliases(indexName string) ([]string, error)
 	GetIndexConfiguration(indexName string) (configuration.Index, error)
 
 	UpdateIndexConfiguration(indexName string, configuration configuration.Index) error
-
-	Reindex(sourceIndexName string, targetIndexName string) error
+	UpdateIndexSettings(indexName string, settings configuration.Settings) error
+
+	CloseIndex(indexName string) error
+	OpenIndex(indexName string) error
+
+	StartReindex(sourceIndexName string, targetIndexName string) (string, error)
+	StartCatchUpReindex(
+		sourceIndexName string,
+		targetIndexName string,
+		timestampField string,
+		since time.Time,
+	) (string, error)
+	GetReindexTask(taskID string) (ReindexTask, error)
+	CancelReindexTask(taskID string) error
+
+	RefreshIndex(indexName string) error
+	CountDocuments(indexName string) (int64, error)
+	SampleDocuments(indexName string, size int) (Documents, error)
+	GetDocuments(indexName string, ids []string) (Documents, error)
+	SearchDocuments(indexName string, search map[string]interface{}) ([]json.RawMessage, error)
+
+	SetWriteBlock(indexName string, blocked bool) error
+
+	GetDocument(indexName string, id string) (*Document, error)
+	CreateDocument(indexName string, id string, source interface{}) (DocumentVersion, error)
+	UpdateDocument(indexName string, id string, source interface{}, version DocumentVersion) (DocumentVersion, error)
+	DeleteDocument(indexName string, id string, version DocumentVersion) error
 }
 
 const v6ClientMajor int64 = 6
 const v7ClientMajor int64 = 7
+const v8ClientMajor int64 = 8
+
+const openSearchV1ClientMajor int64 = 1
+const openSearchV2ClientMajor int64 = 2
+
+const distributionElasticsearch = "elasticsearch"
+const distributionOpenSearch = "opensearch"
 
 // New creates a Client instance.
 func New(options Options) (Client, error) {
@@ -39,14 +76,25 @@ func New(options Options) (Client, error) {
 		return nil, err
 	}
 
-	switch version {
+	if version.distribution == distributionOpenSearch {
+		switch version.major {
+		case openSearchV1ClientMajor, openSearchV2ClientMajor:
+			return NewOpenSearchClient(options)
+		}
+
+		return nil, fmt.Errorf("opensearch version '%d' not supported yet", version.major)
+	}
+
+	switch version.major {
 	case v6ClientMajor:
 		return NewV6Client(options)
 	case v7ClientMajor:
 		return NewV7Client(options)
+	case v8ClientMajor:
+		return NewV8Client(options)
 	}
 
-	return nil, fmt.Errorf("version '%d' not supported yet", version)
+	return nil, fmt.Errorf("version '%d' not supported yet", version.major)
 }
 
 type cluster struct {
@@ -54,7 +102,13 @@ type cluster struct {
 }
 
 type version struct {
-	Number string `json:"number"`
+	Number       string `json:"number"`
+	Distribution string `json:"distribution"`
+}
+
+type clusterVersion struct {
+	distribution string
+	major        int64
 }
 
 func isAValidHost(host string) error {
@@ -74,31 +128,34 @@ func isAValidHost(host string) error {
 	return nil
 }
 
-func getElasticsearchVersion(options Options) (int64, error) {
+func getElasticsearchVersion(options Options) (clusterVersion, error) {
 	if err := isAValidHost(options.Host); err != nil {
-		return 0, fmt.Errorf("elasticsearch host: %s", err)
+		return clusterVersion{}, fmt.Errorf("elasticsearch host: %s", err)
 	}
 
 	req, err := http.NewRequest("GET", options.Host, nil)
 	if err != nil {
-		return 0, err
+		return clusterVersion{}, err
 	}
 
 	if options.User != "" && options.Password != "" {
 		req.SetBasicAuth(options.User, options.Password)
 	}
 
-	client := &http.Client{}
+	client, err := options.newHTTPClient()
+	if err != nil {
+		return clusterVersion{}, err
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return 0, fmt.Errorf("error retrieving elasticsearch version: %s", err)
+		return clusterVersion{}, fmt.Errorf("error retrieving elasticsearch version: %s", err)
 	}
 
 	body, err := ioutil.ReadAll(resp.Body)
 
 	if err != nil {
-		return 0, err
+		return clusterVersion{}, err
 	}
 
 	defer resp.Body.Close()
@@ -106,13 +163,21 @@ func getElasticsearchVersion(options Options) (int64, error) {
 	clusterInfo := cluster{}
 
 	if err := json.Unmarshal(body, &clusterInfo); err != nil {
-		return 0, err
+		return clusterVersion{}, err
 	}
 
 	v, err := semver.NewVersion(clusterInfo.Version.Number)
 	if err != nil {
-		return 0, err
+		return clusterVersion{}, err
+	}
+
+	distribution := clusterInfo.Version.Distribution
+	if distribution == "" {
+		distribution = distributionElasticsearch
 	}
 
-	return v.Major(), nil
+	return clusterVersion{
+		distribution: distribution,
+		major:        v.Major(),
+	}, nil
 }
@@ -3,6 +3,7 @@
 package elasticsearch_test
 
 import (
+	"fmt"
 	"math/rand"
 	"os"
 	"testing"
@@ -42,6 +43,39 @@ func TestElasticSearch_NewV7(t *testing.T) {
 	assert.IsType(t, &elasticsearch.V7Client{}, client)
 }
 
+func TestElasticSearch_NewV8(t *testing.T) {
+	elasticsearchHost := getElasticSearchHost(t, 8)
+	assert.NotEmpty(t, elasticsearchHost)
+	client, err := elasticsearch.New(elasticsearch.Options{
+		Host:     elasticsearchHost,
+		User:     "",
+		Password: "",
+		Debug:    false,
+	})
+
+	assert.NoError(t, err)
+	assert.IsType(t, &elasticsearch.V8Client{}, client)
+}
+
+func TestElasticSearch_NewOpenSearch(t *testing.T) {
+	for _, major := range []int{1, 2} {
+		major := major
+		t.Run(fmt.Sprintf("opensearch v%d", major), func(t *testing.T) {
+			openSearchHost := getOpenSearchHost(t, major)
+			assert.NotEmpty(t, openSearchHost)
+			client, err := elasticsearch.New(elasticsearch.Options{
+				Host:     openSearchHost,
+				User:     "",
+				Password: "",
+				Debug:    false,
+			})
+
+			assert.NoError(t, err)
+			assert.IsType(t, &elasticsearch.OpenSearchClient{}, client)
+		})
+	}
+}
+
 func TestElasticSearch_NewErrors(t *testing.T) {
 	testCases := []struct {
 		name string
@@ -165,6 +199,43 @@ func TestClient_GetAliasedIndex(t *testing.T) {
 	}
 }
 
+func TestClient_ListIndices(t *testing.T) {
+	for _, clientTestCase := range getClientTestCases(t) {
+		clientTestCase := clientTestCase
+		t.Run(clientTestCase.name, func(t *testing.T) {
+			loadTestScenarioWithDocuments(t, clientTestCase.extendedClient)
+
+			indices, err := clientTestCase.client.ListIndices("test-index-*")
+			assert.NoError(t, err)
+			assert.Len(t, indices, 1)
+			assert.Equal(t, existingIndexName, indices[0].Name)
+			assert.Equal(t, "open", indices[0].Status)
+			assert.False(t, indices[0].CreationDate.IsZero())
+
+			indices, err = clientTestCase.client.ListIndices("not-existing-*")
+			assert.NoError(t, err)
+			assert.Empty(t, indices)
+		})
+	}
+}
+
+func TestClient_ListAliases(t *testing.T) {
+	for _, clientTestCase := range getClientTestCases(t) {
+		clientTestCase := clientTestCase
+		t.Run(clientTestCase.name, func(t *testing.T) {
+			loadTestScenario(t, clientTestCase.extendedClient)
+
+			aliases, err := clientTestCase.client.ListAliases("alias-test-*")
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]string{existingAliasName: existingIndexName}, aliases)
+
+			aliases, err = clientTestCase.client.ListAliases(notExistingAliasName)
+			assert.NoError(t, err)
+			assert.Empty(t, aliases)
+		})
+	}
+}
+
 func TestClient_GetIndexConfiguration(t *testing.T) {
 	for _, clientTestCase := range getClientTestCases(t) {
 		clientTestCase := clientTestCase
@@ -223,14 +294,278 @@ func TestClient_Reindex(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, getExtendedBaseConfiguration(t), indexConfiguration)
 
-			err = clientTestCase.client.Reindex(existingIndexName, newIndexName)
+			task, err := elasticsearch.Reindex(
+				clientTestCase.client,
+				existingIndexName,
+				newIndexName,
+				elasticsearch.ReindexWaitOptions{PollInterval: 100 * time.Millisecond},
+			)
 			assert.NoError(t, err)
+			assert.True(t, task.Completed)
+			assert.Equal(t, int64(docsNumber), task.Status.Created)
 
 			assertSameDocuments(t, clientTestCase.extendedClient, existingIndexName, newIndexName)
 		})
 	}
 }
 
+func TestClient_Documents(t *testing.T) {
+	for _, clientTestCase := range getClientTestCases(t) {
+		clientTestCase := clientTestCase
+		t.Run(clientTestCase.name, func(t *testing.T) {
+			loadTestScenarioWithDocuments(t, clientTestCase.extendedClient)
+
+			err := clientTestCase.client.RefreshIndex(existingIndexName)
+			assert.NoError(t, err)
+
+			count, err := clientTestCase.client.CountDocuments(existingIndexName)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(docsNumber), count)
+
+			sample, err := clientTestCase.client.SampleDocuments(existingIndexName, 10)
+			assert.NoError(t, err)
+			assert.Len(t, sample, 10)
+
+			ids := []string{}
+			for id := range sample {
+				ids = append(ids, id)
+			}
+
+			documents, err := clientTestCase.client.GetDocuments(existingIndexName, append(ids, "not-existing-id"))
+			assert.NoError(t, err)
+			assert.Len(t, documents, 10)
+
+			for id, source := range sample {
+				assert.JSONEq(t, string(source), string(documents[id]))
+			}
+
+			_, err = clientTestCase.client.CountDocuments(notExistingIndex)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestClient_SetWriteBlock(t *testing.T) {
+	for _, clientTestCase := range getClientTestCases(t) {
+		clientTestCase := clientTestCase
+		t.Run(clientTestCase.name, func(t *testing.T) {
+			loadTestScenario(t, clientTestCase.extendedClient)
+
+			err := clientTestCase.client.SetWriteBlock(existingIndexName, true)
+			assert.NoError(t, err)
+
+			err = clientTestCase.extendedClient.Load(existingIndexName, createBaseDocument())
+			assert.Error(t, err)
+
+			err = clientTestCase.client.SetWriteBlock(existingIndexName, false)
+			assert.NoError(t, err)
+
+			err = clientTestCase.extendedClient.Load(existingIndexName, createBaseDocument())
+			assert.NoError(t, err)
+
+			indexConfiguration, err := clientTestCase.client.GetIndexConfiguration(existingIndexName)
+			assert.NoError(t, err)
+			assert.Equal(t, getBaseConfiguration(t), indexConfiguration)
+		})
+	}
+}
+
+func TestClient_UpdateIndexSettings(t *testing.T) {
+	for _, clientTestCase := range getClientTestCases(t) {
+		clientTestCase := clientTestCase
+		t.Run(clientTestCase.name, func(t *testing.T) {
+			loadTestScenario(t, clientTestCase.extendedClient)
+
+			err := clientTestCase.client.UpdateIndexSettings(
+				existingIndexName,
+				configuration.Settings{
+					"index": map[string]interface{}{
+						"refresh_interval": "30s",
+					},
+				},
+			)
+			assert.NoError(t, err)
+
+			indexConfiguration, err := clientTestCase.client.GetIndexConfiguration(existingIndexName)
+			assert.NoError(t, err)
+			assert.Equal(t, "30s", indexConfiguration.GetSettings().GetIndexSettings()["refresh_interval"])
+		})
+	}
+}
+
+func TestClient_DeleteIndex(t *testing.T) {
+	for _, clientTestCase := range getClientTestCases(t) {
+		clientTestCase := clientTestCase
+		t.Run(clientTestCase.name, func(t *testing.T) {
+			loadTestScenario(t, clientTestCase.extendedClient)
+
+			err := clientTestCase.client.DeleteIndex(existingIndexName)
+			assert.NoError(t, err)
+
+			exist, err := clientTestCase.client.IndexExist(existingIndexName)
+			assert.NoError(t, err)
+			assert.False(t, exist)
+
+			err = clientTestCase.client.DeleteIndex(notExistingIndex)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestClient_CloseAndOpenIndex(t *testing.T) {
+	for _, clientTestCase := range getClientTestCases(t) {
+		clientTestCase := clientTestCase
+		t.Run(clientTestCase.name, func(t *testing.T) {
+			loadTestScenario(t, clientTestCase.extendedClient)
+
+			err := clientTestCase.client.CloseIndex(existingIndexName)
+			assert.NoError(t, err)
+
+			err = clientTestCase.client.UpdateIndexSettings(
+				existingIndexName,
+				configuration.Settings{
+					"index": map[string]interface{}{
+						"analysis": map[string]interface{}{
+							"analyzer": map[string]interface{}{
+								"folding_analyzer": map[string]interface{}{
+									"tokenizer": "standard",
+									"filter":    []interface{}{"asciifolding"},
+								},
+							},
+						},
+					},
+				},
+			)
+			assert.NoError(t, err)
+
+			err = clientTestCase.client.OpenIndex(existingIndexName)
+			assert.NoError(t, err)
+
+			indexConfiguration, err := clientTestCase.client.GetIndexConfiguration(existingIndexName)
+			assert.NoError(t, err)
+			assert.NotNil(t, indexConfiguration.GetSettings().GetIndexSettings()["analysis"])
+		})
+	}
+}
+
+func TestClient_DocumentVersions(t *testing.T) {
+	for _, clientTestCase := range getClientTestCases(t) {
+		clientTestCase := clientTestCase
+		t.Run(clientTestCase.name, func(t *testing.T) {
+			loadTestScenario(t, clientTestCase.extendedClient)
+
+			document, err := clientTestCase.client.GetDocument(existingIndexName, "lock")
+			assert.NoError(t, err)
+			assert.Nil(t, document)
+
+			document, err = clientTestCase.client.GetDocument(notExistingIndex, "lock")
+			assert.NoError(t, err)
+			assert.Nil(t, document)
+
+			version, err := clientTestCase.client.CreateDocument(existingIndexName, "lock", map[string]interface{}{"name": "a"})
+			assert.NoError(t, err)
+
+			_, err = clientTestCase.client.CreateDocument(existingIndexName, "lock", map[string]interface{}{"name": "b"})
+			assert.True(t, elasticsearch.IsDocumentConflict(err))
+
+			newVersion, err := clientTestCase.client.UpdateDocument(
+				existingIndexName,
+				"lock",
+				map[string]interface{}{"name": "c"},
+				version,
+			)
+			assert.NoError(t, err)
+
+			_, err = clientTestCase.client.UpdateDocument(
+				existingIndexName,
+				"lock",
+				map[string]interface{}{"name": "d"},
+				version,
+			)
+			assert.True(t, elasticsearch.IsDocumentConflict(err))
+
+			document, err = clientTestCase.client.GetDocument(existingIndexName, "lock")
+			assert.NoError(t, err)
+			assert.Equal(t, newVersion, document.Version)
+			assert.JSONEq(t, `{"name":"c"}`, string(document.Source))
+
+			err = clientTestCase.client.DeleteDocument(existingIndexName, "lock", version)
+			assert.True(t, elasticsearch.IsDocumentConflict(err))
+
+			err = clientTestCase.client.DeleteDocument(existingIndexName, "lock", newVersion)
+			assert.NoError(t, err)
+
+			err = clientTestCase.client.DeleteDocument(existingIndexName, "lock", newVersion)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_SearchDocuments(t *testing.T) {
+	for _, clientTestCase := range getClientTestCases(t) {
+		clientTestCase := clientTestCase
+		t.Run(clientTestCase.name, func(t *testing.T) {
+			loadTestScenarioWithDocuments(t, clientTestCase.extendedClient)
+
+			sources, err := clientTestCase.client.SearchDocuments(existingIndexName, map[string]interface{}{
+				"query": map[string]interface{}{"match_all": map[string]interface{}{}},
+				"size":  5,
+			})
+			assert.NoError(t, err)
+			assert.Len(t, sources, 5)
+
+			sources, err = clientTestCase.client.SearchDocuments(notExistingIndex, map[string]interface{}{})
+			assert.NoError(t, err)
+			assert.Empty(t, sources)
+		})
+	}
+}
+
+func TestClient_CatchUpReindex(t *testing.T) {
+	for _, clientTestCase := range getClientTestCases(t) {
+		clientTestCase := clientTestCase
+		t.Run(clientTestCase.name, func(t *testing.T) {
+			loadTestScenarioWithDocuments(t, clientTestCase.extendedClient)
+
+			newIndexName := "new-index-test"
+			err := clientTestCase.client.CreateIndex(newIndexName, getBaseConfiguration(t))
+			assert.NoError(t, err)
+
+			taskID, err := clientTestCase.client.StartCatchUpReindex(
+				existingIndexName,
+				newIndexName,
+				"created_at",
+				time.Now().Add(time.Hour),
+			)
+			assert.NoError(t, err)
+
+			task, err := elasticsearch.WaitForReindex(
+				clientTestCase.client,
+				taskID,
+				elasticsearch.ReindexWaitOptions{PollInterval: 100 * time.Millisecond},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(0), task.Status.Total)
+
+			taskID, err = clientTestCase.client.StartCatchUpReindex(
+				existingIndexName,
+				newIndexName,
+				"created_at",
+				time.Now().Add(-time.Hour),
+			)
+			assert.NoError(t, err)
+
+			task, err = elasticsearch.WaitForReindex(
+				clientTestCase.client,
+				taskID,
+				elasticsearch.ReindexWaitOptions{PollInterval: 100 * time.Millisecond},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(docsNumber), task.Status.Created)
+		})
+	}
+}
+
 func assertSameDocuments(t *testing.T, client extendedClient, index1 string, index2 string) {
 	index1Documents, err := client.GetAll(index1)
 	assert.NoError(t, err)
@@ -263,6 +598,8 @@ func getElasticSearchHost(t *testing.T, version int) string {
 		return os.Getenv("TEST_ELASTICSEARCH_HOST_v6")
 	case 7:
 		return os.Getenv("TEST_ELASTICSEARCH_HOST_v7")
+	case 8:
+		return os.Getenv("TEST_ELASTICSEARCH_HOST_v8")
 	}
 
 	t.Fatalf("Missing elasticsearch version '%d' test configuration", version)
@@ -270,6 +607,19 @@ func getElasticSearchHost(t *testing.T, version int) string {
 	return ""
 }
 
+func getOpenSearchHost(t *testing.T, version int) string {
+	switch version {
+	case 1:
+		return os.Getenv("TEST_OPENSEARCH_HOST_v1")
+	case 2:
+		return os.Getenv("TEST_OPENSEARCH_HOST_v2")
+	}
+
+	t.Fatalf("Missing opensearch version '%d' test configuration", version)
+
+	return ""
+}
+
 func getBaseConfiguration(t *testing.T) configuration.Index {
 	return configuration.New(
 		map[string]interface{}{
@@ -337,6 +687,33 @@ func getClientTestCases(t *testing.T) []ClientTestCase {
 
 	assert.NoError(t, err)
 
+	clientV8, err := elasticsearch.NewV8Client(elasticsearch.Options{
+		Host:     getElasticSearchHost(t, 8),
+		User:     "",
+		Password: "",
+		Debug:    false,
+	})
+
+	assert.NoError(t, err)
+
+	clientOpenSearchV1, err := elasticsearch.NewOpenSearchClient(elasticsearch.Options{
+		Host:     getOpenSearchHost(t, 1),
+		User:     "",
+		Password: "",
+		Debug:    false,
+	})
+
+	assert.NoError(t, err)
+
+	clientOpenSearchV2, err := elasticsearch.NewOpenSearchClient(elasticsearch.Options{
+		Host:     getOpenSearchHost(t, 2),
+		User:     "",
+		Password: "",
+		Debug:    false,
+	})
+
+	assert.NoError(t, err)
+
 	return []ClientTestCase{
 		{
 			name:           "v6",
@@ -348,6 +725,21 @@ func getClientTestCases(t *testing.T) []ClientTestCase {
 			client:         clientV7,
 			extendedClient: elasticsearch.NewV7ClientExtended(clientV7),
 		},
+		{
+			name:           "v8",
+			client:         clientV8,
+			extendedClient: elasticsearch.NewV8ClientExtended(clientV8),
+		},
+		{
+			name:           "opensearch v1",
+			client:         clientOpenSearchV1,
+			extendedClient: elasticsearch.NewOpenSearchClientExtended(clientOpenSearchV1),
+		},
+		{
+			name:           "opensearch v2",
+			client:         clientOpenSearchV2,
+			extendedClient: elasticsearch.NewOpenSearchClientExtended(clientOpenSearchV2),
+		},
 	}
 }
 
@@ -0,0 +1,84 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newVersionTestServer(t *testing.T, number string, distribution string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name":"node","version":{"distribution":"%s","number":"%s"}}`, distribution, number)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func Test_getElasticsearchVersion(t *testing.T) {
+	testCases := []struct {
+		name         string
+		number       string
+		distribution string
+		expected     clusterVersion
+	}{
+		{
+			name:     "elasticsearch 7",
+			number:   "7.9.1",
+			expected: clusterVersion{distribution: distributionElasticsearch, major: 7},
+		},
+		{
+			name:     "elasticsearch 8",
+			number:   "8.10.2",
+			expected: clusterVersion{distribution: distributionElasticsearch, major: 8},
+		},
+		{
+			name:         "opensearch 1",
+			number:       "1.3.12",
+			distribution: "opensearch",
+			expected:     clusterVersion{distribution: distributionOpenSearch, major: 1},
+		},
+		{
+			name:         "opensearch 2",
+			number:       "2.11.0",
+			distribution: "opensearch",
+			expected:     clusterVersion{distribution: distributionOpenSearch, major: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			version, err := getElasticsearchVersion(Options{Host: newVersionTestServer(t, tc.number, tc.distribution)})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, version)
+		})
+	}
+}
+
+func TestNew_RestClients(t *testing.T) {
+	client, err := New(Options{Host: newVersionTestServer(t, "8.10.2", "")})
+	assert.NoError(t, err)
+	assert.IsType(t, &V8Client{}, client)
+
+	client, err = New(Options{Host: newVersionTestServer(t, "1.3.12", "opensearch")})
+	assert.NoError(t, err)
+	assert.IsType(t, &OpenSearchClient{}, client)
+
+	client, err = New(Options{Host: newVersionTestServer(t, "2.11.0", "opensearch")})
+	assert.NoError(t, err)
+	assert.IsType(t, &OpenSearchClient{}, client)
+}
+
+func TestNew_UnsupportedVersions(t *testing.T) {
+	client, err := New(Options{Host: newVersionTestServer(t, "3.0.0", "opensearch")})
+	assert.Error(t, err)
+	assert.Nil(t, client)
+
+	client, err = New(Options{Host: newVersionTestServer(t, "5.6.0", "")})
+	assert.Error(t, err)
+	assert.Nil(t, client)
+}
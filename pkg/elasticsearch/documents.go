@@ -0,0 +1,39 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// DocumentConflictError is returned when a document has been created, or changed, by someone else in the meantime.
+type DocumentConflictError struct {
+	IndexName string
+	ID        string
+}
+
+func (dce *DocumentConflictError) Error() string {
+	return fmt.Sprintf("document '%s' of index '%s' has been changed concurrently", dce.ID, dce.IndexName)
+}
+
+// IsDocumentConflict tells if the error, or one it wraps, is a DocumentConflictError.
+func IsDocumentConflict(err error) bool {
+	var documentConflictError *DocumentConflictError
+
+	return errors.As(err, &documentConflictError)
+}
+
+// Documents holds the _source of some documents indexed by their id.
+type Documents map[string]json.RawMessage
+
+// DocumentVersion identifies a revision of a document, for the optimistic concurrency control.
+type DocumentVersion struct {
+	SeqNo       int64
+	PrimaryTerm int64
+}
+
+// Document is the _source of a document along with its revision.
+type Document struct {
+	Source  json.RawMessage
+	Version DocumentVersion
+}
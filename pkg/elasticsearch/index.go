@@ -3,12 +3,31 @@ package elasticsearch
 import (
 	"fmt"
 	"time"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
 )
 
 func CreateIndexName(aliasName string) string {
 	return fmt.Sprintf("%s-%d", aliasName, time.Now().Unix())
 }
 
+// EnsureIndex creates the index unless it exists, an index created concurrently by another job is not an error.
+func EnsureIndex(client Client, indexName string, index configuration.Index) error {
+	exist, err := client.IndexExist(indexName)
+	if err != nil || exist {
+		return err
+	}
+
+	err = client.CreateIndex(indexName, index)
+	if err != nil {
+		if exist, existErr := client.IndexExist(indexName); existErr == nil && exist {
+			return nil
+		}
+	}
+
+	return err
+}
+
 func ResolveAliasName(prefix string, mappingName string) string {
 	if prefix == "" {
 		return mappingName
@@ -16,3 +35,28 @@ func ResolveAliasName(prefix string, mappingName string) string {
 
 	return fmt.Sprintf("%s-%s", prefix, mappingName)
 }
+
+// addAliasTarget records the index targeted by an alias, aliases targeting several indices are not supported.
+func addAliasTarget(aliases map[string]string, aliasName string, indexName string) error {
+	if currentIndexName, exist := aliases[aliasName]; exist && currentIndexName != indexName {
+		return fmt.Errorf("alias '%s' targets more than 1 index. currently not supported", aliasName)
+	}
+
+	aliases[aliasName] = indexName
+
+	return nil
+}
+
+// writeBlockSettings returns the settings to block, or unblock, the writes on an index.
+// Unblocking resets the setting instead of storing an explicit false on the index.
+func writeBlockSettings(blocked bool) map[string]interface{} {
+	var value interface{}
+
+	if blocked {
+		value = true
+	}
+
+	return map[string]interface{}{
+		"index.blocks.write": value,
+	}
+}
@@ -0,0 +1,128 @@
+package elasticsearch
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IndexInfo describes an index as reported by the _cat/indices API.
+type IndexInfo struct {
+	Name         string    `json:"name"`
+	Health       string    `json:"health"`
+	Status       string    `json:"status"`
+	DocsCount    int64     `json:"docs_count"`
+	StoreSize    string    `json:"store_size"`
+	CreationDate time.Time `json:"creation_date"`
+}
+
+func catIndicesColumns() []string {
+	return []string{"health", "status", "index", "docs.count", "store.size", "creation.date"}
+}
+
+type catIndicesRow struct {
+	Health       string `json:"health"`
+	Status       string `json:"status"`
+	Index        string `json:"index"`
+	DocsCount    string `json:"docs.count"`
+	StoreSize    string `json:"store.size"`
+	CreationDate string `json:"creation.date"`
+}
+
+func (r catIndicesRow) toIndexInfo() IndexInfo {
+	// Closed indices don't report the documents count.
+	docsCount, _ := strconv.ParseInt(r.DocsCount, 10, 64)
+	creationDate, _ := strconv.ParseInt(r.CreationDate, 10, 64)
+
+	return newIndexInfo(r.Index, r.Health, r.Status, docsCount, r.StoreSize, creationDate)
+}
+
+func newIndexInfo(
+	name string,
+	health string,
+	status string,
+	docsCount int64,
+	storeSize string,
+	creationDateMillis int64,
+) IndexInfo {
+	return IndexInfo{
+		Name:         name,
+		Health:       health,
+		Status:       status,
+		DocsCount:    docsCount,
+		StoreSize:    storeSize,
+		CreationDate: time.Unix(0, creationDateMillis*int64(time.Millisecond)),
+	}
+}
+
+// IndexVersion describes an index created for an alias by one of the naming strategies.
+type IndexVersion struct {
+	Strategy NamingStrategy
+	// Sequence orders the versions named by the same strategy, it is 0 for the hash strategy.
+	Sequence int64
+}
+
+// GetIndexVersion tells if the index has been named by one of the naming strategies for the alias.
+func GetIndexVersion(aliasName string, indexName string) (IndexVersion, bool) {
+	if !strings.HasPrefix(indexName, aliasName+"-") {
+		return IndexVersion{}, false
+	}
+
+	suffix := strings.TrimPrefix(indexName, aliasName+"-")
+
+	for i := range namingStrategyNames() {
+		strategy := NamingStrategy(i)
+
+		if sequence, isAVersion := strategy.parseSuffix(suffix); isAVersion {
+			return IndexVersion{Strategy: strategy, Sequence: sequence}, true
+		}
+	}
+
+	return IndexVersion{}, false
+}
+
+// ListIndexVersions returns the indices created for the alias, from the oldest to the newest.
+// The versions are sorted by creation date, since the alias may have been migrated with several naming strategies.
+func ListIndexVersions(client Client, aliasName string) ([]IndexInfo, error) {
+	indices, err := client.ListIndices(aliasName + "-*")
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []IndexInfo{}
+
+	for _, index := range indices {
+		if _, isAVersion := GetIndexVersion(aliasName, index.Name); isAVersion {
+			versions = append(versions, index)
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		if !versions[i].CreationDate.Equal(versions[j].CreationDate) {
+			return versions[i].CreationDate.Before(versions[j].CreationDate)
+		}
+
+		iVersion, _ := GetIndexVersion(aliasName, versions[i].Name)
+		jVersion, _ := GetIndexVersion(aliasName, versions[j].Name)
+
+		if iVersion.Strategy == jVersion.Strategy && iVersion.Sequence != jVersion.Sequence {
+			return iVersion.Sequence < jVersion.Sequence
+		}
+
+		return versions[i].Name < versions[j].Name
+	})
+
+	return versions, nil
+}
+
+// IndexVersionPosition returns the position of the index in the versions, or -1 when it isn't one of them.
+func IndexVersionPosition(versions []IndexInfo, indexName string) int {
+	for position, index := range versions {
+		if index.Name == indexName {
+			return position
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,140 @@
+package elasticsearch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+func TestGetIndexVersion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		indexName       string
+		expectedVersion elasticsearch.IndexVersion
+		expectedOk      bool
+	}{
+		{
+			name:      "timestamp",
+			indexName: "alias-a-630019020",
+			expectedVersion: elasticsearch.IndexVersion{
+				Strategy: elasticsearch.NamingStrategyTimestamp,
+				Sequence: 630019020,
+			},
+			expectedOk: true,
+		},
+		{
+			name:      "date",
+			indexName: "alias-a-1989.12.18-21.17.00.091",
+			expectedVersion: elasticsearch.IndexVersion{
+				Strategy: elasticsearch.NamingStrategyDate,
+				Sequence: 630019020091,
+			},
+			expectedOk: true,
+		},
+		{
+			name:      "version",
+			indexName: "alias-a-v7",
+			expectedVersion: elasticsearch.IndexVersion{
+				Strategy: elasticsearch.NamingStrategyVersion,
+				Sequence: 7,
+			},
+			expectedOk: true,
+		},
+		{
+			name:      "hash",
+			indexName: "alias-a-6f1c0a9e3b2d",
+			expectedVersion: elasticsearch.IndexVersion{
+				Strategy: elasticsearch.NamingStrategyHash,
+			},
+			expectedOk: true,
+		},
+		{
+			name:       "other alias",
+			indexName:  "alias-b-630019020",
+			expectedOk: false,
+		},
+		{
+			name:       "longer alias",
+			indexName:  "alias-a-b-630019020",
+			expectedOk: false,
+		},
+		{
+			name:       "not a version",
+			indexName:  "alias-a-old",
+			expectedOk: false,
+		},
+		{
+			name:       "version without number",
+			indexName:  "alias-a-vnext",
+			expectedOk: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			version, ok := elasticsearch.GetIndexVersion("alias-a", tc.indexName)
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedVersion, version)
+		})
+	}
+}
+
+func TestListIndexVersions(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	client.On("ListIndices", "alias-a-*").Return(
+		[]elasticsearch.IndexInfo{
+			{Name: "alias-a-300"},
+			{Name: "alias-a-b-200"},
+			{Name: "alias-a-100"},
+			{Name: "alias-a-backup"},
+			{Name: "alias-a-v10"},
+			{Name: "alias-a-v9"},
+		},
+		nil,
+	)
+
+	versions, err := elasticsearch.ListIndexVersions(client, "alias-a")
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]elasticsearch.IndexInfo{
+			{Name: "alias-a-100"},
+			{Name: "alias-a-300"},
+			{Name: "alias-a-v9"},
+			{Name: "alias-a-v10"},
+		},
+		versions,
+	)
+}
+
+func TestListIndexVersions_MixedStrategies(t *testing.T) {
+	creationDate := time.Date(2020, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	client := elasticsearch.NewMockClient()
+	client.On("ListIndices", "alias-a-*").Return(
+		[]elasticsearch.IndexInfo{
+			{Name: "alias-a-6f1c0a9e3b2d", CreationDate: creationDate.Add(2 * time.Hour)},
+			{Name: "alias-a-v1", CreationDate: creationDate.Add(time.Hour)},
+			{Name: "alias-a-1589533200", CreationDate: creationDate},
+		},
+		nil,
+	)
+
+	versions, err := elasticsearch.ListIndexVersions(client, "alias-a")
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]elasticsearch.IndexInfo{
+			{Name: "alias-a-1589533200", CreationDate: creationDate},
+			{Name: "alias-a-v1", CreationDate: creationDate.Add(time.Hour)},
+			{Name: "alias-a-6f1c0a9e3b2d", CreationDate: creationDate.Add(2 * time.Hour)},
+		},
+		versions,
+	)
+
+	assert.Equal(t, 1, elasticsearch.IndexVersionPosition(versions, "alias-a-v1"))
+	assert.Equal(t, -1, elasticsearch.IndexVersionPosition(versions, "alias-a-backup"))
+}
@@ -1,6 +1,9 @@
 package elasticsearch
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchy/stretchy/pkg/configuration"
 )
@@ -53,7 +56,117 @@ func (mc *MockClient) UpdateIndexConfiguration(indexName string, configuration c
 	return args.Error(0)
 }
 
-func (mc *MockClient) Reindex(sourceIndexName string, targetIndexName string) error {
+func (mc *MockClient) UpdateIndexSettings(indexName string, settings configuration.Settings) error {
+	args := mc.Called(indexName, settings)
+	return args.Error(0)
+}
+
+func (mc *MockClient) DeleteIndex(indexName string) error {
+	args := mc.Called(indexName)
+	return args.Error(0)
+}
+
+func (mc *MockClient) CloseIndex(indexName string) error {
+	args := mc.Called(indexName)
+	return args.Error(0)
+}
+
+func (mc *MockClient) OpenIndex(indexName string) error {
+	args := mc.Called(indexName)
+	return args.Error(0)
+}
+
+func (mc *MockClient) ListAliases(pattern string) (map[string]string, error) {
+	args := mc.Called(pattern)
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+func (mc *MockClient) GetIndexAliases(indexName string) ([]string, error) {
+	args := mc.Called(indexName)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (mc *MockClient) ListIndices(pattern string) ([]IndexInfo, error) {
+	args := mc.Called(pattern)
+	return args.Get(0).([]IndexInfo), args.Error(1)
+}
+
+func (mc *MockClient) StartReindex(sourceIndexName string, targetIndexName string) (string, error) {
 	args := mc.Called(sourceIndexName, targetIndexName)
+	return args.String(0), args.Error(1)
+}
+
+func (mc *MockClient) StartCatchUpReindex(
+	sourceIndexName string,
+	targetIndexName string,
+	timestampField string,
+	since time.Time,
+) (string, error) {
+	args := mc.Called(sourceIndexName, targetIndexName, timestampField, since)
+	return args.String(0), args.Error(1)
+}
+
+func (mc *MockClient) GetReindexTask(taskID string) (ReindexTask, error) {
+	args := mc.Called(taskID)
+	return args.Get(0).(ReindexTask), args.Error(1)
+}
+
+func (mc *MockClient) CancelReindexTask(taskID string) error {
+	args := mc.Called(taskID)
+	return args.Error(0)
+}
+
+func (mc *MockClient) RefreshIndex(indexName string) error {
+	args := mc.Called(indexName)
+	return args.Error(0)
+}
+
+func (mc *MockClient) CountDocuments(indexName string) (int64, error) {
+	args := mc.Called(indexName)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (mc *MockClient) SampleDocuments(indexName string, size int) (Documents, error) {
+	args := mc.Called(indexName, size)
+	return args.Get(0).(Documents), args.Error(1)
+}
+
+func (mc *MockClient) GetDocuments(indexName string, ids []string) (Documents, error) {
+	args := mc.Called(indexName, ids)
+	return args.Get(0).(Documents), args.Error(1)
+}
+
+func (mc *MockClient) SearchDocuments(indexName string, search map[string]interface{}) ([]json.RawMessage, error) {
+	args := mc.Called(indexName, search)
+	return args.Get(0).([]json.RawMessage), args.Error(1)
+}
+
+func (mc *MockClient) SetWriteBlock(indexName string, blocked bool) error {
+	args := mc.Called(indexName, blocked)
+	return args.Error(0)
+}
+
+func (mc *MockClient) GetDocument(indexName string, id string) (*Document, error) {
+	args := mc.Called(indexName, id)
+	return args.Get(0).(*Document), args.Error(1)
+}
+
+func (mc *MockClient) CreateDocument(indexName string, id string, source interface{}) (DocumentVersion, error) {
+	args := mc.Called(indexName, id, source)
+	return args.Get(0).(DocumentVersion), args.Error(1)
+}
+
+func (mc *MockClient) UpdateDocument(
+	indexName string,
+	id string,
+	source interface{},
+	version DocumentVersion,
+) (DocumentVersion, error) {
+	args := mc.Called(indexName, id, source, version)
+	return args.Get(0).(DocumentVersion), args.Error(1)
+}
+
+func (mc *MockClient) DeleteDocument(indexName string, id string, version DocumentVersion) error {
+	args := mc.Called(indexName, id, version)
 	return args.Error(0)
 }
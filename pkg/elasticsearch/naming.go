@@ -0,0 +1,123 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+)
+
+// NamingStrategy decides how the indices created for an alias are named.
+type NamingStrategy int
+
+const (
+	NamingStrategyTimestamp NamingStrategy = iota
+	NamingStrategyTimestampMillis
+	NamingStrategyDate
+	NamingStrategyVersion
+	NamingStrategyHash
+)
+
+// dateIndexNameLayout only uses characters allowed in index names, and sorts like the dates.
+const dateIndexNameLayout = "2006.01.02-15.04.05.000"
+
+const hashIndexNameLength = 12
+
+func (n NamingStrategy) String() string {
+	return namingStrategyNames()[n]
+}
+
+func namingStrategyNames() []string {
+	return []string{"timestamp", "timestamp-ms", "date", "version", "hash"}
+}
+
+func NewNamingStrategyFromString(name string) (NamingStrategy, error) {
+	for i, strategyName := range namingStrategyNames() {
+		if strategyName == name {
+			return NamingStrategy(i), nil
+		}
+	}
+
+	return NamingStrategyTimestamp, fmt.Errorf("unknown naming strategy '%s'", name)
+}
+
+// NewIndexName returns the name of a new index for the alias:
+//   - timestamp: alias-1589533200
+//   - timestamp-ms: alias-1589533200123
+//   - date: alias-2020.05.15-09.00.00.123
+//   - version: alias-v7, the version following the existing indices of the alias
+//   - hash: alias-6f1c0a9e3b2d, derived from the configuration, so the same configuration gets the same name
+func (n NamingStrategy) NewIndexName(client Client, aliasName string, index configuration.Index) (string, error) {
+	switch n {
+	case NamingStrategyTimestampMillis:
+		return fmt.Sprintf("%s-%d", aliasName, time.Now().UnixNano()/int64(time.Millisecond)), nil
+	case NamingStrategyDate:
+		return fmt.Sprintf("%s-%s", aliasName, time.Now().UTC().Format(dateIndexNameLayout)), nil
+	case NamingStrategyVersion:
+		return nextVersionIndexName(client, aliasName)
+	case NamingStrategyHash:
+		hash, err := index.Hash()
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s-%s", aliasName, hash[:hashIndexNameLength]), nil
+	}
+
+	return CreateIndexName(aliasName), nil
+}
+
+// parseSuffix returns the sequence encoded in the suffix of an index name, when the suffix follows the strategy.
+// The hash strategy doesn't encode any sequence.
+func (n NamingStrategy) parseSuffix(suffix string) (int64, bool) {
+	switch n {
+	case NamingStrategyTimestamp, NamingStrategyTimestampMillis:
+		return parsePositiveInt(suffix)
+	case NamingStrategyDate:
+		date, err := time.Parse(dateIndexNameLayout, suffix)
+		if err != nil {
+			return 0, false
+		}
+
+		return date.UnixNano() / int64(time.Millisecond), true
+	case NamingStrategyVersion:
+		if !strings.HasPrefix(suffix, "v") {
+			return 0, false
+		}
+
+		return parsePositiveInt(strings.TrimPrefix(suffix, "v"))
+	case NamingStrategyHash:
+		return 0, len(suffix) == hashIndexNameLength && strings.Trim(suffix, "0123456789abcdef") == ""
+	}
+
+	return 0, false
+}
+
+func parsePositiveInt(value string) (int64, bool) {
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number <= 0 {
+		return 0, false
+	}
+
+	return number, true
+}
+
+func nextVersionIndexName(client Client, aliasName string) (string, error) {
+	indices, err := client.ListIndices(aliasName + "-*")
+	if err != nil {
+		return "", err
+	}
+
+	lastVersion := int64(0)
+
+	for _, index := range indices {
+		version, isAVersion := GetIndexVersion(aliasName, index.Name)
+		if isAVersion && version.Strategy == NamingStrategyVersion && version.Sequence > lastVersion {
+			lastVersion = version.Sequence
+		}
+	}
+
+	return fmt.Sprintf("%s-v%d", aliasName, lastVersion+1), nil
+}
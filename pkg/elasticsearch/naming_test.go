@@ -0,0 +1,104 @@
+package elasticsearch_test
+
+import (
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/configuration"
+	"github.com/stretchy/stretchy/pkg/elasticsearch"
+)
+
+func TestNewNamingStrategyFromString(t *testing.T) {
+	for _, name := range []string{"timestamp", "timestamp-ms", "date", "version", "hash"} {
+		strategy, err := elasticsearch.NewNamingStrategyFromString(name)
+		assert.NoError(t, err)
+		assert.Equal(t, name, strategy.String())
+	}
+
+	_, err := elasticsearch.NewNamingStrategyFromString("uuid")
+	assert.EqualError(t, err, "unknown naming strategy 'uuid'")
+}
+
+func TestNamingStrategy_NewIndexName(t *testing.T) {
+	patch := monkey.Patch(time.Now, func() time.Time {
+		return time.Date(1989, 12, 18, 21, 17, 0, int(91*time.Millisecond), time.UTC)
+	})
+	defer patch.Unpatch()
+
+	index := configuration.New(
+		configuration.Mappings{
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{"type": "keyword"},
+			},
+		},
+		configuration.Settings{},
+	)
+
+	hash, err := index.Hash()
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		strategy          elasticsearch.NamingStrategy
+		expectedIndexName string
+	}{
+		{
+			strategy:          elasticsearch.NamingStrategyTimestamp,
+			expectedIndexName: "alias-a-630019020",
+		},
+		{
+			strategy:          elasticsearch.NamingStrategyTimestampMillis,
+			expectedIndexName: "alias-a-630019020091",
+		},
+		{
+			strategy:          elasticsearch.NamingStrategyDate,
+			expectedIndexName: "alias-a-1989.12.18-21.17.00.091",
+		},
+		{
+			strategy:          elasticsearch.NamingStrategyVersion,
+			expectedIndexName: "alias-a-v10",
+		},
+		{
+			strategy:          elasticsearch.NamingStrategyHash,
+			expectedIndexName: "alias-a-" + hash[:12],
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.strategy.String(), func(t *testing.T) {
+			client := elasticsearch.NewMockClient()
+			client.On("ListIndices", "alias-a-*").Return(
+				[]elasticsearch.IndexInfo{
+					{Name: "alias-a-v2"},
+					{Name: "alias-a-v9"},
+					{Name: "alias-a-630019000"},
+					{Name: "alias-a-b-v20"},
+				},
+				nil,
+			)
+
+			indexName, err := tc.strategy.NewIndexName(client, "alias-a", index)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedIndexName, indexName)
+
+			version, isAVersion := elasticsearch.GetIndexVersion("alias-a", indexName)
+			assert.True(t, isAVersion)
+
+			// Both timestamps are numbers, the milliseconds are recognized as a timestamp.
+			if tc.strategy != elasticsearch.NamingStrategyTimestampMillis {
+				assert.Equal(t, tc.strategy, version.Strategy)
+			}
+		})
+	}
+}
+
+func TestNamingStrategy_NewIndexName_FirstVersion(t *testing.T) {
+	client := elasticsearch.NewMockClient()
+	client.On("ListIndices", "alias-a-*").Return([]elasticsearch.IndexInfo{}, nil)
+
+	indexName, err := elasticsearch.NamingStrategyVersion.NewIndexName(client, "alias-a", configuration.Index{})
+	assert.NoError(t, err)
+	assert.Equal(t, "alias-a-v1", indexName)
+}
@@ -0,0 +1,22 @@
+package elasticsearch
+
+// OpenSearchClient is a client for OpenSearch 1.x and 2.x clusters. Create one by calling NewOpenSearchClient.
+//
+// OpenSearch exposes the same typeless REST API as Elasticsearch 7.10 and later.
+type OpenSearchClient struct {
+	*restClient
+}
+
+// NewOpenSearchClient creates an OpenSearchClient instance.
+func NewOpenSearchClient(
+	options Options,
+) (*OpenSearchClient, error) {
+	client, err := newRestClient(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenSearchClient{
+		restClient: client,
+	}, nil
+}
@@ -1,9 +1,201 @@
 package elasticsearch
 
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
 // Options is a set of flags to configure a Client.
 type Options struct {
 	Host     string
 	User     string
 	Password string
 	Debug    bool
+	// CAFile is a PEM bundle of the authorities trusted besides the system ones, e.g. an internal CA.
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and key presented to the cluster, for mutual TLS.
+	CertFile string
+	KeyFile  string
+	// ServerName is the name verified in the certificate of the cluster, the host name by default.
+	ServerName string
+	// InsecureSkipVerify accepts any certificate of the cluster, it must only be used for testing.
+	InsecureSkipVerify bool
+	// APIKey is the base64 encoded "id:api_key" pair of an API key, sent as "Authorization: ApiKey".
+	// APIKeyFile is read instead when APIKey is empty.
+	APIKey     string
+	APIKeyFile string
+	// BearerToken is sent as "Authorization: Bearer", e.g. a service account token or an OAuth2 access token.
+	// BearerTokenFile is read instead when BearerToken is empty.
+	BearerToken     string
+	BearerTokenFile string
+	// Headers are added to every request, e.g. for a proxy in front of the cluster.
+	Headers map[string]string
+}
+
+// hasCredentials tells if the requests are authenticated by a password or a token.
+func (o Options) hasCredentials() bool {
+	return (o.User != "" && o.Password != "") ||
+		o.APIKey != "" || o.APIKeyFile != "" ||
+		o.BearerToken != "" || o.BearerTokenFile != ""
+}
+
+// requireTLS refuses the credentials sent over an unencrypted connection. Only the 8.x and OpenSearch clients
+// require it, as these clusters are secured with TLS by default, while 6.x and 7.x clusters are commonly
+// reached over plain HTTP with basic auth.
+func (o Options) requireTLS() error {
+	hostURL, err := url.Parse(o.Host)
+	if o.hasCredentials() && (err != nil || hostURL.Scheme != "https") {
+		return fmt.Errorf("refusing to send credentials over an unencrypted connection to '%s'", o.Host)
+	}
+
+	return nil
+}
+
+// newHTTPClient returns the HTTP client of the version probe and of the clients, configured by the TLS options.
+func (o Options) newHTTPClient() (*http.Client, error) {
+	tlsConfig, err := o.newTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := o.newHeaders()
+	if err != nil {
+		return nil, err
+	}
+
+	// The default transport keeps its proxy, timeouts and connection pool settings.
+	defaultTransport := http.DefaultTransport.(*http.Transport).Clone()
+	defaultTransport.TLSClientConfig = tlsConfig
+
+	var transport http.RoundTripper = defaultTransport
+
+	if len(headers) > 0 {
+		transport = &headerTransport{headers: headers, transport: transport}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// newHeaders returns the extra headers and the Authorization header of the API key or the bearer token.
+// The basic auth stays set by the clients themselves.
+func (o Options) newHeaders() (http.Header, error) {
+	apiKey, err := readSecret(o.APIKey, o.APIKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("elasticsearch API key: %s", err)
+	}
+
+	bearerToken, err := readSecret(o.BearerToken, o.BearerTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("elasticsearch bearer token: %s", err)
+	}
+
+	methods := 0
+
+	for _, isSet := range []bool{o.User != "" && o.Password != "", apiKey != "", bearerToken != ""} {
+		if isSet {
+			methods++
+		}
+	}
+
+	if methods > 1 {
+		return nil, fmt.Errorf("elasticsearch authentication: only one of basic auth, API key and bearer token can be used")
+	}
+
+	headers := http.Header{}
+
+	for name, value := range o.Headers {
+		headers.Set(name, value)
+	}
+
+	if apiKey != "" {
+		headers.Set("Authorization", "ApiKey "+apiKey)
+	}
+
+	if bearerToken != "" {
+		headers.Set("Authorization", "Bearer "+bearerToken)
+	}
+
+	return headers, nil
+}
+
+// readSecret returns the value, or the trimmed content of the file when the value is empty.
+func readSecret(value string, file string) (string, error) {
+	if value != "" || file == "" {
+		return value, nil
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	secret := strings.TrimSpace(string(content))
+	if secret == "" {
+		return "", fmt.Errorf("'%s' is empty", file)
+	}
+
+	return secret, nil
+}
+
+// headerTransport adds headers to the requests, whichever client sends them.
+type headerTransport struct {
+	headers   http.Header
+	transport http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+
+	return t.transport.RoundTrip(req)
+}
+
+func (o Options) newTLSConfig() (*tls.Config, error) {
+	//nolint:gosec
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.CAFile != "" {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+
+		content, err := ioutil.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("elasticsearch CA file: %s", err)
+		}
+
+		if !rootCAs.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("elasticsearch CA file: no certificate found in '%s'", o.CAFile)
+		}
+
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, fmt.Errorf("elasticsearch client certificate: both the certificate and the key files are required")
+	}
+
+	if o.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("elasticsearch client certificate: %s", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
 }
@@ -0,0 +1,343 @@
+package elasticsearch
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePEM(t *testing.T, name string, blockType string, content []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+
+	assert.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: content}), 0600))
+
+	return path
+}
+
+// writeClientCertificate creates a self-signed client certificate, and returns it with its file and its key file.
+func writeClientCertificate(t *testing.T) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "stretchy"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	content, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(content)
+	assert.NoError(t, err)
+
+	encodedKey, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile := writePEM(t, "client.crt", "CERTIFICATE", content)
+	keyFile := writePEM(t, "client.key", "EC PRIVATE KEY", encodedKey)
+
+	return certificate, certFile, keyFile
+}
+
+func newTLSVersionTestServer(t *testing.T, clientCA *x509.Certificate) (*httptest.Server, string) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"node","version":{"number":"8.10.2"}}`)
+	}))
+
+	if clientCA != nil {
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(clientCA)
+
+		server.TLS = &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+		}
+	}
+
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server, writePEM(t, "ca.crt", "CERTIFICATE", server.Certificate().Raw)
+}
+
+func TestOptions_TLS(t *testing.T) {
+	server, caFile := newTLSVersionTestServer(t, nil)
+
+	_, err := getElasticsearchVersion(Options{Host: server.URL})
+	assert.Error(t, err, "the certificate of the test server is not trusted by default")
+
+	version, err := getElasticsearchVersion(Options{Host: server.URL, CAFile: caFile})
+	assert.NoError(t, err)
+	assert.Equal(t, clusterVersion{distribution: distributionElasticsearch, major: 8}, version)
+
+	_, err = getElasticsearchVersion(Options{Host: server.URL, CAFile: caFile, ServerName: "elasticsearch.internal"})
+	assert.Error(t, err, "the certificate of the test server is not valid for this name")
+
+	_, err = getElasticsearchVersion(Options{Host: server.URL, InsecureSkipVerify: true})
+	assert.NoError(t, err)
+
+	client, err := New(Options{Host: server.URL, CAFile: caFile})
+	assert.NoError(t, err)
+	assert.IsType(t, &V8Client{}, client)
+}
+
+func TestOptions_TLS_ClientCertificate(t *testing.T) {
+	clientCertificate, certFile, keyFile := writeClientCertificate(t)
+	server, caFile := newTLSVersionTestServer(t, clientCertificate)
+
+	_, err := getElasticsearchVersion(Options{Host: server.URL, CAFile: caFile})
+	assert.Error(t, err, "the test server requires a client certificate")
+
+	_, err = getElasticsearchVersion(Options{Host: server.URL, CAFile: caFile, CertFile: certFile, KeyFile: keyFile})
+	assert.NoError(t, err)
+}
+
+func TestOptions_newHTTPClient_DefaultTransport(t *testing.T) {
+	httpClient, err := Options{ServerName: "elasticsearch.internal"}.newHTTPClient()
+	assert.NoError(t, err)
+
+	transport, isATransport := httpClient.Transport.(*http.Transport)
+	assert.True(t, isATransport)
+
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+
+	assert.Equal(t, "elasticsearch.internal", transport.TLSClientConfig.ServerName)
+	assert.NotNil(t, transport.Proxy)
+	assert.NotNil(t, transport.DialContext)
+	assert.Equal(t, defaultTransport.MaxIdleConns, transport.MaxIdleConns)
+	assert.Equal(t, defaultTransport.IdleConnTimeout, transport.IdleConnTimeout)
+	assert.Equal(t, defaultTransport.TLSHandshakeTimeout, transport.TLSHandshakeTimeout)
+	assert.Equal(t, defaultTransport.ForceAttemptHTTP2, transport.ForceAttemptHTTP2)
+}
+
+func TestOptions_newTLSConfig_Errors(t *testing.T) {
+	_, certFile, keyFile := writeClientCertificate(t)
+
+	testCases := []struct {
+		name     string
+		options  Options
+		expected string
+	}{
+		{
+			name:     "missing CA file",
+			options:  Options{CAFile: filepath.Join(t.TempDir(), "missing.crt")},
+			expected: "elasticsearch CA file: open ",
+		},
+		{
+			name:     "CA file without certificate",
+			options:  Options{CAFile: keyFile},
+			expected: "elasticsearch CA file: no certificate found in ",
+		},
+		{
+			name:     "certificate without key",
+			options:  Options{CertFile: certFile},
+			expected: "elasticsearch client certificate: both the certificate and the key files are required",
+		},
+		{
+			name:     "invalid key",
+			options:  Options{CertFile: certFile, KeyFile: certFile},
+			expected: "elasticsearch client certificate: ",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.options.newTLSConfig()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestOptions_Headers(t *testing.T) {
+	apiKeyFile := filepath.Join(t.TempDir(), "api-key")
+	assert.NoError(t, ioutil.WriteFile(apiKeyFile, []byte("aWQ6a2V5\n"), 0600))
+
+	testCases := []struct {
+		name                  string
+		options               Options
+		expectedAuthorization string
+	}{
+		{
+			name:                  "API key",
+			options:               Options{APIKey: "aWQ6a2V5"},
+			expectedAuthorization: "ApiKey aWQ6a2V5",
+		},
+		{
+			name:                  "API key file",
+			options:               Options{APIKeyFile: apiKeyFile},
+			expectedAuthorization: "ApiKey aWQ6a2V5",
+		},
+		{
+			name:                  "bearer token",
+			options:               Options{BearerToken: "service-token"},
+			expectedAuthorization: "Bearer service-token",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			requests := []*http.Request{}
+
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r)
+				fmt.Fprint(w, `{"name":"node","version":{"number":"7.17.0"}}`)
+			}))
+			defer server.Close()
+
+			options := tc.options
+			options.Host = server.URL
+			options.CAFile = writePEM(t, "ca.crt", "CERTIFICATE", server.Certificate().Raw)
+			options.Headers = map[string]string{"X-Tenant": "search"}
+
+			client, err := New(options)
+			assert.NoError(t, err)
+			assert.IsType(t, &V7Client{}, client)
+
+			_, err = client.IndexExist("index")
+			assert.NoError(t, err)
+
+			assert.Len(t, requests, 2, "the version probe and the v7 client")
+
+			for _, request := range requests {
+				assert.Equal(t, tc.expectedAuthorization, request.Header.Get("Authorization"))
+				assert.Equal(t, "search", request.Header.Get("X-Tenant"))
+			}
+		})
+	}
+}
+
+func TestOptions_RefusesCredentialsOverHTTP(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options Options
+		version string
+	}{
+		{
+			name:    "basic auth",
+			options: Options{User: "user", Password: "password"},
+			version: `{"number":"8.11.0"}`,
+		},
+		{
+			name:    "API key",
+			options: Options{APIKey: "aWQ6a2V5"},
+			version: `{"number":"8.11.0"}`,
+		},
+		{
+			name:    "bearer token",
+			options: Options{BearerToken: "service-token"},
+			version: `{"number":"2.11.0","distribution":"opensearch"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"name":"node","version":%s}`, tc.version)
+			}))
+			defer server.Close()
+
+			options := tc.options
+			options.Host = server.URL
+
+			expectedErr := "refusing to send credentials over an unencrypted connection to '" + server.URL + "'"
+
+			_, err := New(options)
+			assert.EqualError(t, err, expectedErr)
+
+			_, err = NewV8Client(options)
+			assert.EqualError(t, err, expectedErr)
+
+			_, err = NewOpenSearchClient(options)
+			assert.EqualError(t, err, expectedErr)
+		})
+	}
+}
+
+func TestOptions_CredentialsOverHTTP_V6V7(t *testing.T) {
+	for _, number := range []string{"6.8.23", "7.17.0"} {
+		number := number
+		t.Run(number, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				user, password, ok := r.BasicAuth()
+				assert.True(t, ok)
+				assert.Equal(t, "user", user)
+				assert.Equal(t, "password", password)
+
+				fmt.Fprintf(w, `{"name":"node","version":{"number":"%s"}}`, number)
+			}))
+			defer server.Close()
+
+			client, err := New(Options{Host: server.URL, User: "user", Password: "password"})
+			assert.NoError(t, err)
+
+			exist, err := client.IndexExist("products")
+			assert.NoError(t, err)
+			assert.True(t, exist)
+		})
+	}
+}
+
+func TestOptions_newHeaders_Errors(t *testing.T) {
+	emptyFile := filepath.Join(t.TempDir(), "empty")
+	assert.NoError(t, ioutil.WriteFile(emptyFile, []byte("\n"), 0600))
+
+	testCases := []struct {
+		name     string
+		options  Options
+		expected string
+	}{
+		{
+			name:     "missing API key file",
+			options:  Options{APIKeyFile: filepath.Join(t.TempDir(), "missing")},
+			expected: "elasticsearch API key: open ",
+		},
+		{
+			name:     "empty bearer token file",
+			options:  Options{BearerTokenFile: emptyFile},
+			expected: "elasticsearch bearer token: '" + emptyFile + "' is empty",
+		},
+		{
+			name:     "basic auth and API key",
+			options:  Options{User: "user", Password: "password", APIKey: "aWQ6a2V5"},
+			expected: "elasticsearch authentication: only one of basic auth, API key and bearer token can be used",
+		},
+		{
+			name:     "API key and bearer token",
+			options:  Options{APIKey: "aWQ6a2V5", BearerToken: "service-token"},
+			expected: "elasticsearch authentication: only one of basic auth, API key and bearer token can be used",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.options.newHeaders()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const maxReportedReindexFailures = 10
+
+// ReindexStatus holds the counters reported by a reindex task.
+type ReindexStatus struct {
+	Total            int64 `json:"total"`
+	Created          int64 `json:"created"`
+	Updated          int64 `json:"updated"`
+	Deleted          int64 `json:"deleted"`
+	Batches          int64 `json:"batches"`
+	VersionConflicts int64 `json:"version_conflicts"`
+	Noops            int64 `json:"noops"`
+}
+
+// Processed returns the number of documents the task has already gone through.
+func (rs ReindexStatus) Processed() int64 {
+	return rs.Created + rs.Updated + rs.Deleted + rs.VersionConflicts + rs.Noops
+}
+
+// ReindexFailure is a document, or a search shard, that could not be reindexed.
+type ReindexFailure struct {
+	Index  string
+	ID     string
+	Reason string
+}
+
+func (rf ReindexFailure) String() string {
+	if rf.ID == "" {
+		return fmt.Sprintf("index '%s': %s", rf.Index, rf.Reason)
+	}
+
+	return fmt.Sprintf("document '%s' of index '%s': %s", rf.ID, rf.Index, rf.Reason)
+}
+
+// ReindexTask is a snapshot of a reindex running as a task on the cluster.
+type ReindexTask struct {
+	ID          string
+	Completed   bool
+	Status      ReindexStatus
+	RunningTime time.Duration
+	Error       string
+	Failures    []ReindexFailure
+}
+
+// ReindexError is returned when a completed reindex didn't copy every document.
+type ReindexError struct {
+	TaskID           string
+	Total            int64
+	Copied           int64
+	VersionConflicts int64
+	Failures         []ReindexFailure
+}
+
+func (re *ReindexError) Error() string {
+	message := fmt.Sprintf(
+		"reindex task '%s' copied %d/%d documents (version conflicts: %d, failures: %d)",
+		re.TaskID,
+		re.Copied,
+		re.Total,
+		re.VersionConflicts,
+		len(re.Failures),
+	)
+
+	failures := []string{}
+
+	for i, failure := range re.Failures {
+		if i == maxReportedReindexFailures {
+			failures = append(failures, fmt.Sprintf("and %d more", len(re.Failures)-i))
+			break
+		}
+
+		failures = append(failures, failure.String())
+	}
+
+	if len(failures) == 0 {
+		return message
+	}
+
+	return fmt.Sprintf("%s:\n\t%s", message, strings.Join(failures, "\n\t"))
+}
+
+// Validate returns a ReindexError when the task didn't copy every document of the source index.
+func (rt ReindexTask) Validate() error {
+	copied := rt.Status.Created + rt.Status.Updated
+
+	if len(rt.Failures) == 0 && rt.Status.VersionConflicts == 0 && copied >= rt.Status.Total {
+		return nil
+	}
+
+	return &ReindexError{
+		TaskID:           rt.ID,
+		Total:            rt.Status.Total,
+		Copied:           copied,
+		VersionConflicts: rt.Status.VersionConflicts,
+		Failures:         rt.Failures,
+	}
+}
+
+// DocsPerSecond returns the average throughput of the task.
+func (rt ReindexTask) DocsPerSecond() float64 {
+	if rt.RunningTime <= 0 {
+		return 0
+	}
+
+	return float64(rt.Status.Processed()) / rt.RunningTime.Seconds()
+}
+
+// ETA returns the estimated remaining time, 0 when it cannot be estimated yet.
+func (rt ReindexTask) ETA() time.Duration {
+	docsPerSecond := rt.DocsPerSecond()
+	remaining := rt.Status.Total - rt.Status.Processed()
+
+	if docsPerSecond <= 0 || remaining <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(remaining) / docsPerSecond * float64(time.Second))
+}
+
+func (rt ReindexTask) String() string {
+	return fmt.Sprintf(
+		"%d/%d docs (created: %d, updated: %d) %.1f docs/s ETA %s",
+		rt.Status.Processed(),
+		rt.Status.Total,
+		rt.Status.Created,
+		rt.Status.Updated,
+		rt.DocsPerSecond(),
+		rt.ETA().Round(time.Second),
+	)
+}
+
+type taskErrorCause struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+type taskResponse struct {
+	Completed bool `json:"completed"`
+	Task      struct {
+		Status             ReindexStatus `json:"status"`
+		RunningTimeInNanos int64         `json:"running_time_in_nanos"`
+	} `json:"task"`
+	Response *struct {
+		ReindexStatus
+		Failures []struct {
+			Index string `json:"index"`
+			ID    string `json:"id"`
+			// Bulk failures carry a cause while search failures carry a reason.
+			Cause  *taskErrorCause `json:"cause"`
+			Reason *taskErrorCause `json:"reason"`
+		} `json:"failures"`
+	} `json:"response"`
+	Error *taskErrorCause `json:"error"`
+}
+
+// parseReindexTask decodes the body returned by the _tasks API, it's shared by every client.
+func parseReindexTask(taskID string, body []byte) (ReindexTask, error) {
+	response := taskResponse{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ReindexTask{}, err
+	}
+
+	task := ReindexTask{
+		ID:          taskID,
+		Completed:   response.Completed,
+		Status:      response.Task.Status,
+		RunningTime: time.Duration(response.Task.RunningTimeInNanos),
+	}
+
+	if response.Response != nil {
+		task.Status = response.Response.ReindexStatus
+
+		for _, failure := range response.Response.Failures {
+			cause := failure.Cause
+			if cause == nil {
+				cause = failure.Reason
+			}
+
+			reindexFailure := ReindexFailure{
+				Index: failure.Index,
+				ID:    failure.ID,
+			}
+
+			if cause != nil {
+				reindexFailure.Reason = fmt.Sprintf("%s: %s", cause.Type, cause.Reason)
+			}
+
+			task.Failures = append(task.Failures, reindexFailure)
+		}
+	}
+
+	if response.Error != nil {
+		task.Error = fmt.Sprintf("%s: %s", response.Error.Type, response.Error.Reason)
+	}
+
+	return task, nil
+}
+
+// ReindexProgressFunc is called on each poll of a running reindex task.
+type ReindexProgressFunc func(task ReindexTask)
+
+// ReindexWaitOptions configures how a reindex task is polled.
+type ReindexWaitOptions struct {
+	PollInterval time.Duration
+	// Timeout is how long the task may run before it's cancelled, 0 waits until it's completed.
+	Timeout time.Duration
+	// MaxPollErrors is the number of consecutive polls allowed to fail, e.g. while a node restarts.
+	MaxPollErrors int
+	OnProgress    ReindexProgressFunc
+}
+
+// WaitForReindex polls a reindex task until it's completed.
+// The task is cancelled when it's given up on, so that it doesn't keep filling the target index.
+func WaitForReindex(
+	client Client,
+	taskID string,
+	options ReindexWaitOptions,
+) (ReindexTask, error) {
+	var deadline time.Time
+
+	if options.Timeout > 0 {
+		deadline = time.Now().Add(options.Timeout)
+	}
+
+	lastTask := ReindexTask{}
+	pollErrors := 0
+
+	for {
+		task, err := client.GetReindexTask(taskID)
+
+		if err != nil {
+			pollErrors++
+
+			if pollErrors > options.MaxPollErrors {
+				return lastTask, cancelReindex(client, taskID, fmt.Errorf("polling reindex task '%s': %s", taskID, err))
+			}
+		} else {
+			pollErrors = 0
+			lastTask = task
+
+			if options.OnProgress != nil {
+				options.OnProgress(task)
+			}
+
+			if task.Completed {
+				if task.Error != "" {
+					return task, fmt.Errorf("reindex task '%s' failed: %s", taskID, task.Error)
+				}
+
+				return task, nil
+			}
+		}
+
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return lastTask, cancelReindex(
+				client,
+				taskID,
+				fmt.Errorf("reindex task '%s' not completed within %s", taskID, options.Timeout),
+			)
+		}
+
+		time.Sleep(options.PollInterval)
+	}
+}
+
+func cancelReindex(client Client, taskID string, err error) error {
+	if cancelErr := client.CancelReindexTask(taskID); cancelErr != nil {
+		return fmt.Errorf("%s, and the task could not be cancelled: %s", err, cancelErr)
+	}
+
+	return fmt.Errorf("%s, the task has been cancelled", err)
+}
+
+// Reindex starts a reindex task and waits for its completion.
+func Reindex(
+	client Client,
+	sourceIndexName string,
+	targetIndexName string,
+	options ReindexWaitOptions,
+) (ReindexTask, error) {
+	taskID, err := client.StartReindex(sourceIndexName, targetIndexName)
+	if err != nil {
+		return ReindexTask{}, err
+	}
+
+	return WaitForReindex(client, taskID, options)
+}
@@ -0,0 +1,255 @@
+package elasticsearch
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseReindexTask(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected ReindexTask
+	}{
+		{
+			name: "running",
+			body: `{"completed":false,"task":{"node":"n","id":1,` +
+				`"status":{"total":100,"created":20,"updated":5,"batches":1},"running_time_in_nanos":5000000000}}`,
+			expected: ReindexTask{
+				ID:          "n:1",
+				Completed:   false,
+				Status:      ReindexStatus{Total: 100, Created: 20, Updated: 5, Batches: 1},
+				RunningTime: 5 * time.Second,
+			},
+		},
+		{
+			name: "completed",
+			body: `{"completed":true,"task":{"status":{"total":100,"created":90},"running_time_in_nanos":10000000000},` +
+				`"response":{"total":100,"created":100}}`,
+			expected: ReindexTask{
+				ID:          "n:1",
+				Completed:   true,
+				Status:      ReindexStatus{Total: 100, Created: 100},
+				RunningTime: 10 * time.Second,
+			},
+		},
+		{
+			name: "failed",
+			body: `{"completed":true,"task":{"status":{"total":100}},` +
+				`"error":{"type":"index_not_found_exception","reason":"no such index"}}`,
+			expected: ReindexTask{
+				ID:        "n:1",
+				Completed: true,
+				Status:    ReindexStatus{Total: 100},
+				Error:     "index_not_found_exception: no such index",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			task, err := parseReindexTask("n:1", []byte(tc.body))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, task)
+		})
+	}
+
+	_, err := parseReindexTask("n:1", []byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestReindexTask_Progress(t *testing.T) {
+	task := ReindexTask{
+		Status:      ReindexStatus{Total: 1000, Created: 150, Updated: 50},
+		RunningTime: 10 * time.Second,
+	}
+
+	assert.Equal(t, int64(200), task.Status.Processed())
+	assert.Equal(t, float64(20), task.DocsPerSecond())
+	assert.Equal(t, 40*time.Second, task.ETA())
+	assert.Equal(t, "200/1000 docs (created: 150, updated: 50) 20.0 docs/s ETA 40s", task.String())
+
+	assert.Equal(t, time.Duration(0), ReindexTask{}.ETA())
+	assert.Equal(t, float64(0), ReindexTask{}.DocsPerSecond())
+}
+
+func TestReindex(t *testing.T) {
+	client := NewMockClient()
+	client.On("StartReindex", "source", "target").Return("n:1", nil)
+	client.On("GetReindexTask", "n:1").Return(ReindexTask{ID: "n:1"}, nil).Once()
+	client.On("GetReindexTask", "n:1").Return(ReindexTask{ID: "n:1", Completed: true}, nil).Once()
+
+	progressCalls := 0
+	task, err := Reindex(client, "source", "target", ReindexWaitOptions{
+		PollInterval: time.Millisecond,
+		OnProgress: func(task ReindexTask) {
+			progressCalls++
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, task.Completed)
+	assert.Equal(t, 2, progressCalls)
+	client.AssertExpectations(t)
+}
+
+func TestReindex_Errors(t *testing.T) {
+	client := NewMockClient()
+	client.On("StartReindex", "source", "target").Return("", errors.New("cannot start"))
+
+	_, err := Reindex(client, "source", "target", ReindexWaitOptions{PollInterval: time.Millisecond})
+	assert.Error(t, err)
+
+	client = NewMockClient()
+	client.On("StartReindex", "source", "target").Return("n:1", nil)
+	client.On("GetReindexTask", "n:1").Return(ReindexTask{ID: "n:1", Completed: true, Error: "failure"}, nil)
+
+	task, err := Reindex(client, "source", "target", ReindexWaitOptions{PollInterval: time.Millisecond})
+	assert.Error(t, err)
+	assert.Equal(t, "failure", task.Error)
+
+}
+
+func TestWaitForReindex_PollErrors(t *testing.T) {
+	client := NewMockClient()
+	client.On("GetReindexTask", "n:1").Return(ReindexTask{}, errors.New("unavailable")).Twice()
+	client.On("GetReindexTask", "n:1").Return(ReindexTask{ID: "n:1", Completed: true}, nil).Once()
+
+	task, err := WaitForReindex(client, "n:1", ReindexWaitOptions{PollInterval: time.Millisecond, MaxPollErrors: 2})
+	assert.NoError(t, err)
+	assert.True(t, task.Completed)
+	client.AssertExpectations(t)
+
+	client = NewMockClient()
+	client.On("GetReindexTask", "n:1").Return(ReindexTask{ID: "n:1"}, nil).Once()
+	client.On("GetReindexTask", "n:1").Return(ReindexTask{}, errors.New("unavailable"))
+	client.On("CancelReindexTask", "n:1").Return(nil)
+
+	task, err = WaitForReindex(client, "n:1", ReindexWaitOptions{PollInterval: time.Millisecond, MaxPollErrors: 2})
+	assert.EqualError(t, err, "polling reindex task 'n:1': unavailable, the task has been cancelled")
+	assert.Equal(t, "n:1", task.ID, "the last known state of the task is returned")
+	client.AssertNumberOfCalls(t, "GetReindexTask", 4)
+	client.AssertExpectations(t)
+}
+
+func TestWaitForReindex_Timeout(t *testing.T) {
+	client := NewMockClient()
+	client.On("GetReindexTask", "n:1").Return(ReindexTask{ID: "n:1"}, nil)
+	client.On("CancelReindexTask", "n:1").Return(errors.New("not found"))
+
+	options := ReindexWaitOptions{PollInterval: time.Millisecond, Timeout: 10 * time.Millisecond}
+
+	_, err := WaitForReindex(client, "n:1", options)
+	assert.EqualError(
+		t,
+		err,
+		"reindex task 'n:1' not completed within 10ms, and the task could not be cancelled: not found",
+	)
+	client.AssertExpectations(t)
+}
+
+func Test_parseReindexTask_Failures(t *testing.T) {
+	task, err := parseReindexTask("n:1", []byte(`{
+		"completed":true,
+		"task":{"status":{"total":3}},
+		"response":{
+			"total":3,
+			"created":1,
+			"version_conflicts":1,
+			"failures":[
+				{
+					"index":"target",
+					"id":"doc-1",
+					"cause":{"type":"mapper_parsing_exception","reason":"failed to parse field"},
+					"status":400
+				},
+				{"index":"source","shard":0,"reason":{"type":"search_exception","reason":"shard failure"}}
+			]
+		}
+	}`))
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]ReindexFailure{
+			{Index: "target", ID: "doc-1", Reason: "mapper_parsing_exception: failed to parse field"},
+			{Index: "source", Reason: "search_exception: shard failure"},
+		},
+		task.Failures,
+	)
+	assert.Equal(t, int64(1), task.Status.VersionConflicts)
+}
+
+func TestReindexTask_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		task        ReindexTask
+		expectError bool
+	}{
+		{
+			name:        "every document copied",
+			task:        ReindexTask{Status: ReindexStatus{Total: 10, Created: 8, Updated: 2}},
+			expectError: false,
+		},
+		{
+			name:        "empty index",
+			task:        ReindexTask{},
+			expectError: false,
+		},
+		{
+			name:        "missing documents",
+			task:        ReindexTask{Status: ReindexStatus{Total: 10, Created: 8}},
+			expectError: true,
+		},
+		{
+			name:        "version conflicts",
+			task:        ReindexTask{Status: ReindexStatus{Total: 10, Created: 10, VersionConflicts: 1}},
+			expectError: true,
+		},
+		{
+			name: "failures",
+			task: ReindexTask{
+				Status:   ReindexStatus{Total: 10, Created: 10},
+				Failures: []ReindexFailure{{Index: "target", ID: "1", Reason: "bad"}},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.task.Validate()
+			if !tc.expectError {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.IsType(t, &ReindexError{}, err)
+		})
+	}
+}
+
+func TestReindexError_Error(t *testing.T) {
+	failures := []ReindexFailure{}
+	for i := 0; i < maxReportedReindexFailures+2; i++ {
+		failures = append(failures, ReindexFailure{Index: "target", ID: "doc", Reason: "bad"})
+	}
+
+	reindexError := &ReindexError{TaskID: "n:1", Total: 20, Copied: 8, Failures: failures}
+	message := reindexError.Error()
+
+	assert.Contains(t, message, "reindex task 'n:1' copied 8/20 documents (version conflicts: 0, failures: 12)")
+	assert.Contains(t, message, "document 'doc' of index 'target': bad")
+	assert.Contains(t, message, "and 2 more")
+
+	assert.Equal(
+		t,
+		"reindex task 'n:1' copied 8/20 documents (version conflicts: 0, failures: 0)",
+		(&ReindexError{TaskID: "n:1", Total: 20, Copied: 8}).Error(),
+	)
+}
@@ -0,0 +1,670 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stretchy/stretchy/pkg/configuration"
+)
+
+// RequestError is returned when the cluster answers with a non 2xx status code.
+type RequestError struct {
+	Status int
+	Type   string
+	Reason string
+}
+
+func (e *RequestError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf(
+			"elasticsearch: error %d (%s): %s [type=%s]",
+			e.Status,
+			http.StatusText(e.Status),
+			e.Reason,
+			e.Type,
+		)
+	}
+
+	return fmt.Sprintf("elasticsearch: error %d (%s)", e.Status, http.StatusText(e.Status))
+}
+
+func isNotFound(err error) bool {
+	requestError, ok := err.(*RequestError)
+
+	return ok && requestError.Status == http.StatusNotFound
+}
+
+func isConflict(err error) bool {
+	requestError, ok := err.(*RequestError)
+
+	return ok && requestError.Status == http.StatusConflict
+}
+
+// restClient talks to the REST API of the cluster without any client library.
+// It is used for the versions that are not supported by olivere/elastic.
+type restClient struct {
+	host       string
+	user       string
+	password   string
+	httpClient *http.Client
+	logger     *log.Logger
+}
+
+func newRestClient(options Options) (*restClient, error) {
+	if err := isAValidHost(options.Host); err != nil {
+		return nil, fmt.Errorf("elasticsearch host: %s", err)
+	}
+
+	if err := options.requireTLS(); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := options.newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &restClient{
+		host:       strings.TrimRight(options.Host, "/"),
+		user:       options.User,
+		password:   options.Password,
+		httpClient: httpClient,
+	}
+
+	if options.Debug == true {
+		client.logger = newLogger()
+	}
+
+	return client, nil
+}
+
+func (c *restClient) do(method string, path string, params url.Values, body interface{}, result interface{}) error {
+	var bodyReader io.Reader
+
+	if body != nil {
+		encodedBody, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		bodyReader = bytes.NewReader(encodedBody)
+	}
+
+	requestURL := c.host + path
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequest(method, requestURL, bodyReader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.user != "" && c.password != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+
+	if c.logger != nil {
+		c.logger.Printf("%s %s", method, requestURL)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if c.logger != nil {
+		c.logger.Printf("%d %s", resp.StatusCode, string(responseBody))
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newRequestError(resp.StatusCode, responseBody)
+	}
+
+	if result == nil || method == http.MethodHead {
+		return nil
+	}
+
+	return json.Unmarshal(responseBody, result)
+}
+
+func newRequestError(status int, body []byte) *RequestError {
+	requestError := &RequestError{
+		Status: status,
+	}
+
+	response := struct {
+		Error json.RawMessage `json:"error"`
+	}{}
+
+	if err := json.Unmarshal(body, &response); err != nil || len(response.Error) == 0 {
+		return requestError
+	}
+
+	// The error can be either a plain string or an object with type and reason.
+	if err := json.Unmarshal(response.Error, &requestError.Reason); err == nil {
+		return requestError
+	}
+
+	details := struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	}{}
+
+	if err := json.Unmarshal(response.Error, &details); err == nil {
+		requestError.Type = details.Type
+		requestError.Reason = details.Reason
+	}
+
+	return requestError
+}
+
+func (c *restClient) IndexExist(indexName string) (bool, error) {
+	err := c.do(http.MethodHead, "/"+url.PathEscape(indexName), nil, nil, nil)
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (c *restClient) AliasExist(aliasName string) (bool, error) {
+	err := c.do(http.MethodHead, "/_alias/"+url.PathEscape(aliasName), nil, nil, nil)
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (c *restClient) CreateIndex(indexName string, mapping configuration.Index) error {
+	return c.do(http.MethodPut, "/"+url.PathEscape(indexName), nil, mapping, nil)
+}
+
+func (c *restClient) CreateAlias(aliasName string, indexName string) error {
+	return c.UpdateAlias(aliasName, indexName)
+}
+
+func (c *restClient) UpdateAlias(aliasName string, indexName string) error {
+	return c.do(
+		http.MethodPost,
+		"/_aliases",
+		nil,
+		map[string]interface{}{
+			"actions": []map[string]interface{}{
+				{"remove": map[string]interface{}{"index": "*", "alias": aliasName}},
+				{"add": map[string]interface{}{"index": indexName, "alias": aliasName}},
+			},
+		},
+		nil,
+	)
+}
+
+func (c *restClient) GetAliasedIndex(aliasName string) (string, error) {
+	aliasResult := map[string]struct {
+		Aliases map[string]interface{} `json:"aliases"`
+	}{}
+
+	if err := c.do(http.MethodGet, "/_alias/"+url.PathEscape(aliasName), nil, nil, &aliasResult); err != nil {
+		return "", err
+	}
+
+	if len(aliasResult) > 1 {
+		return "", fmt.Errorf("alias '%s' targets more than 1 index. currently not supported", aliasName)
+	}
+
+	for index := range aliasResult {
+		return index, nil
+	}
+
+	return "", fmt.Errorf("alias '%s' doesn't target any index", aliasName)
+}
+
+func (c *restClient) ListAliases(pattern string) (map[string]string, error) {
+	aliasResult := map[string]struct {
+		Aliases map[string]interface{} `json:"aliases"`
+	}{}
+
+	if err := c.do(http.MethodGet, "/_alias/"+url.PathEscape(pattern), nil, nil, &aliasResult); err != nil {
+		if isNotFound(err) {
+			return map[string]string{}, nil
+		}
+
+		return nil, err
+	}
+
+	aliases := map[string]string{}
+
+	for indexName, index := range aliasResult {
+		for aliasName := range index.Aliases {
+			if err := addAliasTarget(aliases, aliasName, indexName); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return aliases, nil
+}
+
+// GetIndexAliases returns the names of the aliases targeting the index.
+func (c *restClient) GetIndexAliases(indexName string) ([]string, error) {
+	aliasResult := map[string]struct {
+		Aliases map[string]interface{} `json:"aliases"`
+	}{}
+
+	if err := c.do(http.MethodGet, "/"+url.PathEscape(indexName)+"/_alias", nil, nil, &aliasResult); err != nil {
+		return nil, err
+	}
+
+	aliasNames := []string{}
+
+	for aliasName := range aliasResult[indexName].Aliases {
+		aliasNames = append(aliasNames, aliasName)
+	}
+
+	return aliasNames, nil
+}
+
+func (c *restClient) ListIndices(pattern string) ([]IndexInfo, error) {
+	rows := []catIndicesRow{}
+
+	if err := c.do(
+		http.MethodGet,
+		"/_cat/indices/"+url.PathEscape(pattern),
+		url.Values{
+			"format": []string{"json"},
+			"h":      []string{strings.Join(catIndicesColumns(), ",")},
+		},
+		nil,
+		&rows,
+	); err != nil {
+		return nil, err
+	}
+
+	indices := []IndexInfo{}
+
+	for _, row := range rows {
+		indices = append(indices, row.toIndexInfo())
+	}
+
+	return indices, nil
+}
+
+func (c *restClient) GetIndexConfiguration(indexName string) (configuration.Index, error) {
+	indexResult := map[string]struct {
+		Mappings configuration.Mappings `json:"mappings"`
+		Settings configuration.Settings `json:"settings"`
+	}{}
+
+	if err := c.do(http.MethodGet, "/"+url.PathEscape(indexName), nil, nil, &indexResult); err != nil {
+		return configuration.Index{}, err
+	}
+
+	index, exist := indexResult[indexName]
+	if !exist {
+		return configuration.Index{}, fmt.Errorf("index '%s' not found in the response", indexName)
+	}
+
+	return configuration.New(
+		index.Mappings,
+		index.Settings,
+	), nil
+}
+
+func (c *restClient) StartReindex(sourceIndexName string, targetIndexName string) (string, error) {
+	result := struct {
+		Task string `json:"task"`
+	}{}
+
+	if err := c.do(
+		http.MethodPost,
+		"/_reindex",
+		url.Values{
+			"wait_for_completion": []string{"false"},
+			"refresh":             []string{"true"},
+		},
+		map[string]interface{}{
+			"source": map[string]interface{}{"index": sourceIndexName},
+			"dest":   map[string]interface{}{"index": targetIndexName},
+		},
+		&result,
+	); err != nil {
+		return "", err
+	}
+
+	return result.Task, nil
+}
+
+func (c *restClient) StartCatchUpReindex(
+	sourceIndexName string,
+	targetIndexName string,
+	timestampField string,
+	since time.Time,
+) (string, error) {
+	result := struct {
+		Task string `json:"task"`
+	}{}
+
+	if err := c.do(
+		http.MethodPost,
+		"/_reindex",
+		url.Values{
+			"wait_for_completion": []string{"false"},
+			"refresh":             []string{"true"},
+		},
+		map[string]interface{}{
+			"source": map[string]interface{}{
+				"index": sourceIndexName,
+				"query": map[string]interface{}{
+					"range": map[string]interface{}{
+						timestampField: map[string]interface{}{
+							"gte":    since.UnixNano() / int64(time.Millisecond),
+							"format": "epoch_millis",
+						},
+					},
+				},
+			},
+			"dest": map[string]interface{}{"index": targetIndexName},
+		},
+		&result,
+	); err != nil {
+		return "", err
+	}
+
+	return result.Task, nil
+}
+
+func (c *restClient) GetReindexTask(taskID string) (ReindexTask, error) {
+	body := json.RawMessage{}
+
+	if err := c.do(http.MethodGet, "/_tasks/"+url.PathEscape(taskID), nil, nil, &body); err != nil {
+		return ReindexTask{}, err
+	}
+
+	return parseReindexTask(taskID, body)
+}
+
+func (c *restClient) CancelReindexTask(taskID string) error {
+	return c.do(http.MethodPost, "/_tasks/"+url.PathEscape(taskID)+"/_cancel", nil, nil, nil)
+}
+
+func (c *restClient) UpdateIndexConfiguration(indexName string, configuration configuration.Index) error {
+	return c.do(
+		http.MethodPut,
+		"/"+url.PathEscape(indexName)+"/_mapping",
+		nil,
+		configuration.GetMappings(),
+		nil,
+	)
+}
+
+func (c *restClient) UpdateIndexSettings(indexName string, settings configuration.Settings) error {
+	return c.do(http.MethodPut, "/"+url.PathEscape(indexName)+"/_settings", nil, settings, nil)
+}
+
+func (c *restClient) DeleteIndex(indexName string) error {
+	return c.do(http.MethodDelete, "/"+url.PathEscape(indexName), nil, nil, nil)
+}
+
+func (c *restClient) CloseIndex(indexName string) error {
+	return c.do(http.MethodPost, "/"+url.PathEscape(indexName)+"/_close", nil, nil, nil)
+}
+
+func (c *restClient) OpenIndex(indexName string) error {
+	return c.do(http.MethodPost, "/"+url.PathEscape(indexName)+"/_open", nil, nil, nil)
+}
+
+func (c *restClient) RefreshIndex(indexName string) error {
+	return c.do(http.MethodPost, "/"+url.PathEscape(indexName)+"/_refresh", nil, nil, nil)
+}
+
+func (c *restClient) CountDocuments(indexName string) (int64, error) {
+	result := struct {
+		Count int64 `json:"count"`
+	}{}
+
+	if err := c.do(http.MethodGet, "/"+url.PathEscape(indexName)+"/_count", nil, nil, &result); err != nil {
+		return 0, err
+	}
+
+	return result.Count, nil
+}
+
+func (c *restClient) SampleDocuments(indexName string, size int) (Documents, error) {
+	searchResult := struct {
+		Hits struct {
+			Hits []struct {
+				ID     string          `json:"_id"`
+				Source json.RawMessage `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}{}
+
+	if err := c.do(
+		http.MethodPost,
+		"/"+url.PathEscape(indexName)+"/_search",
+		nil,
+		map[string]interface{}{
+			"size": size,
+			"query": map[string]interface{}{
+				"function_score": map[string]interface{}{
+					"query":        map[string]interface{}{"match_all": map[string]interface{}{}},
+					"random_score": map[string]interface{}{},
+				},
+			},
+		},
+		&searchResult,
+	); err != nil {
+		return nil, err
+	}
+
+	documents := Documents{}
+
+	for _, hit := range searchResult.Hits.Hits {
+		documents[hit.ID] = hit.Source
+	}
+
+	return documents, nil
+}
+
+func (c *restClient) GetDocuments(indexName string, ids []string) (Documents, error) {
+	documents := Documents{}
+
+	if len(ids) == 0 {
+		return documents, nil
+	}
+
+	mgetResult := struct {
+		Docs []struct {
+			ID     string          `json:"_id"`
+			Found  bool            `json:"found"`
+			Source json.RawMessage `json:"_source"`
+		} `json:"docs"`
+	}{}
+
+	if err := c.do(
+		http.MethodPost,
+		"/"+url.PathEscape(indexName)+"/_mget",
+		nil,
+		map[string]interface{}{"ids": ids},
+		&mgetResult,
+	); err != nil {
+		return nil, err
+	}
+
+	for _, doc := range mgetResult.Docs {
+		if doc.Found {
+			documents[doc.ID] = doc.Source
+		}
+	}
+
+	return documents, nil
+}
+
+// SearchDocuments returns the _source of the hits, it returns no document when the index doesn't exist.
+func (c *restClient) SearchDocuments(indexName string, search map[string]interface{}) ([]json.RawMessage, error) {
+	searchResult := struct {
+		Hits struct {
+			Hits []struct {
+				Source json.RawMessage `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}{}
+
+	if err := c.do(
+		http.MethodPost,
+		"/"+url.PathEscape(indexName)+"/_search",
+		nil,
+		search,
+		&searchResult,
+	); err != nil {
+		if isNotFound(err) {
+			return []json.RawMessage{}, nil
+		}
+
+		return nil, err
+	}
+
+	sources := []json.RawMessage{}
+
+	for _, hit := range searchResult.Hits.Hits {
+		sources = append(sources, hit.Source)
+	}
+
+	return sources, nil
+}
+
+func (c *restClient) SetWriteBlock(indexName string, blocked bool) error {
+	return c.do(
+		http.MethodPut,
+		"/"+url.PathEscape(indexName)+"/_settings",
+		nil,
+		writeBlockSettings(blocked),
+		nil,
+	)
+}
+
+// GetDocument returns nil when either the document or the index doesn't exist.
+func (c *restClient) GetDocument(indexName string, id string) (*Document, error) {
+	getResult := struct {
+		Found       bool            `json:"found"`
+		SeqNo       int64           `json:"_seq_no"`
+		PrimaryTerm int64           `json:"_primary_term"`
+		Source      json.RawMessage `json:"_source"`
+	}{}
+
+	if err := c.do(http.MethodGet, documentPath(indexName, id), nil, nil, &getResult); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if !getResult.Found {
+		return nil, nil
+	}
+
+	return &Document{
+		Source: getResult.Source,
+		Version: DocumentVersion{
+			SeqNo:       getResult.SeqNo,
+			PrimaryTerm: getResult.PrimaryTerm,
+		},
+	}, nil
+}
+
+func (c *restClient) CreateDocument(indexName string, id string, source interface{}) (DocumentVersion, error) {
+	return c.indexDocument(indexName, id, source, url.Values{"op_type": []string{"create"}})
+}
+
+func (c *restClient) UpdateDocument(
+	indexName string,
+	id string,
+	source interface{},
+	version DocumentVersion,
+) (DocumentVersion, error) {
+	documentVersion, err := c.indexDocument(indexName, id, source, documentVersionParams(version))
+	if isNotFound(err) {
+		return DocumentVersion{}, &DocumentConflictError{IndexName: indexName, ID: id}
+	}
+
+	return documentVersion, err
+}
+
+func (c *restClient) indexDocument(
+	indexName string,
+	id string,
+	source interface{},
+	params url.Values,
+) (DocumentVersion, error) {
+	indexResult := struct {
+		SeqNo       int64 `json:"_seq_no"`
+		PrimaryTerm int64 `json:"_primary_term"`
+	}{}
+
+	if err := c.do(http.MethodPut, documentPath(indexName, id), params, source, &indexResult); err != nil {
+		if isConflict(err) {
+			return DocumentVersion{}, &DocumentConflictError{IndexName: indexName, ID: id}
+		}
+
+		return DocumentVersion{}, err
+	}
+
+	return DocumentVersion{SeqNo: indexResult.SeqNo, PrimaryTerm: indexResult.PrimaryTerm}, nil
+}
+
+// DeleteDocument doesn't fail when the document has already been deleted.
+func (c *restClient) DeleteDocument(indexName string, id string, version DocumentVersion) error {
+	err := c.do(http.MethodDelete, documentPath(indexName, id), documentVersionParams(version), nil, nil)
+
+	switch {
+	case isNotFound(err):
+		return nil
+	case isConflict(err):
+		return &DocumentConflictError{IndexName: indexName, ID: id}
+	}
+
+	return err
+}
+
+func documentPath(indexName string, id string) string {
+	return "/" + url.PathEscape(indexName) + "/_doc/" + url.PathEscape(id)
+}
+
+func documentVersionParams(version DocumentVersion) url.Values {
+	return url.Values{
+		"if_seq_no":       []string{strconv.FormatInt(version.SeqNo, 10)},
+		"if_primary_term": []string{strconv.FormatInt(version.PrimaryTerm, 10)},
+	}
+}
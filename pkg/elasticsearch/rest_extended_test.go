@@ -0,0 +1,122 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RestClientExtended adds test helpers to the clients built on top of restClient.
+type RestClientExtended struct {
+	*restClient
+	client Client
+}
+
+func NewV8ClientExtended(client *V8Client) *RestClientExtended {
+	return &RestClientExtended{
+		restClient: client.restClient,
+		client:     client,
+	}
+}
+
+func (re *RestClientExtended) GetClient() Client {
+	return re.client
+}
+
+func (re *RestClientExtended) Load(indexName string, documents ...map[string]interface{}) error {
+	for _, d := range documents {
+		if err := re.do(
+			http.MethodPost,
+			"/"+url.PathEscape(indexName)+"/_doc",
+			nil,
+			d,
+			nil,
+		); err != nil {
+			return err
+		}
+	}
+
+	return re.do(http.MethodPost, "/"+url.PathEscape(indexName)+"/_refresh", nil, nil, nil)
+}
+
+func (re *RestClientExtended) CleanupIndex(indexName string) error {
+	return re.do(
+		http.MethodPost,
+		"/"+url.PathEscape(indexName)+"/_delete_by_query",
+		url.Values{
+			"conflicts": []string{"proceed"},
+			"refresh":   []string{"true"},
+		},
+		map[string]interface{}{
+			"query": map[string]interface{}{"match_all": map[string]interface{}{}},
+		},
+		nil,
+	)
+}
+
+// Cleanup deletes the indices one by one since wildcard deletions are disabled by default since v8.
+func (re *RestClientExtended) Cleanup() error {
+	indices := []struct {
+		Index string `json:"index"`
+	}{}
+
+	if err := re.do(
+		http.MethodGet,
+		"/_cat/indices",
+		url.Values{"format": []string{"json"}, "h": []string{"index"}},
+		nil,
+		&indices,
+	); err != nil {
+		return err
+	}
+
+	for _, index := range indices {
+		if strings.HasPrefix(index.Index, ".") {
+			continue
+		}
+
+		if err := re.do(http.MethodDelete, "/"+url.PathEscape(index.Index), nil, nil, nil); err != nil {
+			return fmt.Errorf("deleting index '%s': %s", index.Index, err)
+		}
+	}
+
+	return nil
+}
+
+func (re *RestClientExtended) GetAll(indexName string) ([]map[string]interface{}, error) {
+	searchResult := struct {
+		Hits struct {
+			Hits []struct {
+				Source map[string]interface{} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}{}
+
+	if err := re.do(
+		http.MethodPost,
+		"/"+url.PathEscape(indexName)+"/_search",
+		nil,
+		map[string]interface{}{
+			"size":  1000,
+			"query": map[string]interface{}{"match_all": map[string]interface{}{}},
+		},
+		&searchResult,
+	); err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, len(searchResult.Hits.Hits))
+	for i, hit := range searchResult.Hits.Hits {
+		results[i] = hit.Source
+	}
+
+	return results, nil
+}
+
+func NewOpenSearchClientExtended(client *OpenSearchClient) *RestClientExtended {
+	return &RestClientExtended{
+		restClient: client.restClient,
+		client:     client,
+	}
+}
@@ -0,0 +1,258 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/configuration"
+)
+
+func newTestRestClient(t *testing.T, handler http.HandlerFunc) *restClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := newRestClient(Options{Host: server.URL})
+	assert.NoError(t, err)
+
+	return client
+}
+
+func Test_newRestClient_RefusesCredentialsOverHTTP(t *testing.T) {
+	_, err := newRestClient(Options{Host: "http://localhost:9200", User: "user", Password: "password"})
+	assert.Error(t, err)
+
+	_, err = newRestClient(Options{Host: "https://localhost:9200", User: "user", Password: "password"})
+	assert.NoError(t, err)
+
+	_, err = newRestClient(Options{Host: "localhost:9200"})
+	assert.Error(t, err)
+}
+
+func Test_newRequestError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		body     string
+		expected *RequestError
+	}{
+		{
+			name:     "plain string error",
+			status:   http.StatusNotFound,
+			body:     `{"error":"alias [a] missing","status":404}`,
+			expected: &RequestError{Status: http.StatusNotFound, Reason: "alias [a] missing"},
+		},
+		{
+			name:   "object error",
+			status: http.StatusBadRequest,
+			body:   `{"error":{"type":"illegal_argument_exception","reason":"bad"},"status":400}`,
+			expected: &RequestError{
+				Status: http.StatusBadRequest,
+				Type:   "illegal_argument_exception",
+				Reason: "bad",
+			},
+		},
+		{
+			name:     "empty body",
+			status:   http.StatusInternalServerError,
+			body:     ``,
+			expected: &RequestError{Status: http.StatusInternalServerError},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			requestError := newRequestError(tc.status, []byte(tc.body))
+			assert.Equal(t, tc.expected, requestError)
+			assert.NotEmpty(t, requestError.Error())
+		})
+	}
+}
+
+func TestRestClient_GetAliasedIndex(t *testing.T) {
+	client := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_alias/my-alias":
+			fmt.Fprint(w, `{"my-index-1":{"aliases":{"my-alias":{}}}}`)
+		case "/_alias/multiple-alias":
+			fmt.Fprint(w, `{"my-index-1":{"aliases":{"multiple-alias":{}}},"my-index-2":{"aliases":{"multiple-alias":{}}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"alias [missing] missing","status":404}`)
+		}
+	})
+
+	indexName, err := client.GetAliasedIndex("my-alias")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-index-1", indexName)
+
+	_, err = client.GetAliasedIndex("multiple-alias")
+	assert.Error(t, err)
+
+	_, err = client.GetAliasedIndex("missing")
+	assert.True(t, isNotFound(err))
+
+	exist, err := client.AliasExist("missing")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func TestRestClient_GetIndexConfiguration(t *testing.T) {
+	client := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"my-index":{
+			"aliases":{},
+			"mappings":{"properties":{"id":{"type":"integer"}}},
+			"settings":{"index":{"number_of_shards":"1","uuid":"abc","provided_name":"my-index"}}
+		}}`)
+	})
+
+	index, err := client.GetIndexConfiguration("my-index")
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		configuration.New(
+			configuration.Mappings{
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{"type": "integer"},
+				},
+			},
+			configuration.Settings{
+				"index": map[string]interface{}{"number_of_shards": "1"},
+			},
+		),
+		index,
+	)
+
+	_, err = client.GetIndexConfiguration("another-index")
+	assert.Error(t, err)
+}
+
+func TestRestClient_ListIndices(t *testing.T) {
+	client := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/_cat/indices/my-alias-*", r.URL.Path)
+		assert.Equal(t, "json", r.URL.Query().Get("format"))
+
+		fmt.Fprint(w, `[
+			{
+				"health":"green","status":"open","index":"my-alias-1",
+				"docs.count":"12","store.size":"4.6kb","creation.date":"1527077221644"
+			},
+			{
+				"health":null,"status":"close","index":"my-alias-2",
+				"docs.count":null,"store.size":null,"creation.date":"1527077221000"
+			}
+		]`)
+	})
+
+	indices, err := client.ListIndices("my-alias-*")
+	assert.NoError(t, err)
+	assert.Len(t, indices, 2)
+	assert.Equal(t, "my-alias-1", indices[0].Name)
+	assert.Equal(t, int64(12), indices[0].DocsCount)
+	assert.Equal(t, int64(1527077221644), indices[0].CreationDate.UnixNano()/int64(time.Millisecond))
+	assert.Equal(t, "close", indices[1].Status)
+	assert.Equal(t, int64(0), indices[1].DocsCount)
+}
+
+func TestRestClient_ListAliases(t *testing.T) {
+	client := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_alias/my-*":
+			fmt.Fprint(w, `{"my-index-1":{"aliases":{"my-alias":{}}},"my-index-2":{"aliases":{"my-other-alias":{}}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"alias [missing] missing","status":404}`)
+		}
+	})
+
+	aliases, err := client.ListAliases("my-*")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"my-alias": "my-index-1", "my-other-alias": "my-index-2"}, aliases)
+
+	aliases, err = client.ListAliases("missing")
+	assert.NoError(t, err)
+	assert.Empty(t, aliases)
+}
+
+func TestRestClient_GetIndexAliases(t *testing.T) {
+	client := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/my-index-1/_alias", r.URL.Path)
+		fmt.Fprint(w, `{"my-index-1":{"aliases":{"my-alias":{}}}}`)
+	})
+
+	aliasNames, err := client.GetIndexAliases("my-index-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"my-alias"}, aliasNames)
+}
+
+func TestRestClient_Documents(t *testing.T) {
+	client := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/my-index/_doc/my-id", r.URL.Path)
+
+		switch {
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"_id":"my-id","_seq_no":4,"_primary_term":2,"found":true,"_source":{"owner":"me"}}`)
+		case r.Method == http.MethodPut && r.URL.Query().Get("op_type") == "create":
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"error":{"type":"version_conflict_engine_exception","reason":"document already exists"}}`)
+		case r.Method == http.MethodPut:
+			assert.Equal(t, "4", r.URL.Query().Get("if_seq_no"))
+			assert.Equal(t, "2", r.URL.Query().Get("if_primary_term"))
+			fmt.Fprint(w, `{"_id":"my-id","_seq_no":5,"_primary_term":2,"result":"updated"}`)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"_id":"my-id","result":"not_found"}`)
+		}
+	})
+
+	document, err := client.GetDocument("my-index", "my-id")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"owner":"me"}`, string(document.Source))
+	assert.Equal(t, DocumentVersion{SeqNo: 4, PrimaryTerm: 2}, document.Version)
+
+	_, err = client.CreateDocument("my-index", "my-id", map[string]string{"owner": "me"})
+	assert.Equal(t, &DocumentConflictError{IndexName: "my-index", ID: "my-id"}, err)
+
+	version, err := client.UpdateDocument("my-index", "my-id", map[string]string{"owner": "me"}, document.Version)
+	assert.NoError(t, err)
+	assert.Equal(t, DocumentVersion{SeqNo: 5, PrimaryTerm: 2}, version)
+
+	assert.NoError(t, client.DeleteDocument("my-index", "my-id", version))
+}
+
+func TestRestClient_GetDocument_NotFound(t *testing.T) {
+	client := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"type":"index_not_found_exception","reason":"no such index [my-index]"},"status":404}`)
+	})
+
+	document, err := client.GetDocument("my-index", "my-id")
+	assert.NoError(t, err)
+	assert.Nil(t, document)
+}
+
+func TestRestClient_SearchDocuments(t *testing.T) {
+	client := newTestRestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/my-index/_search":
+			assert.Equal(t, http.MethodPost, r.Method)
+			fmt.Fprint(w, `{"hits":{"total":{"value":2},"hits":[{"_id":"2","_source":{"n":2}},{"_id":"1","_source":{"n":1}}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"type":"index_not_found_exception","reason":"no such index"},"status":404}`)
+		}
+	})
+
+	sources, err := client.SearchDocuments("my-index", map[string]interface{}{"size": 2})
+	assert.NoError(t, err)
+	assert.Len(t, sources, 2)
+	assert.JSONEq(t, `{"n":2}`, string(sources[0]))
+
+	sources, err = client.SearchDocuments("missing-index", map[string]interface{}{"size": 2})
+	assert.NoError(t, err)
+	assert.Empty(t, sources)
+}
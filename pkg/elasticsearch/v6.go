@@ -2,7 +2,10 @@ package elasticsearch
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"time"
 
 	"github.com/olivere/elastic"
 	"github.com/stretchy/stretchy/pkg/configuration"
@@ -30,10 +33,16 @@ func NewV6Client(
 func newOlivereV6(
 	options Options,
 ) (*elastic.Client, error) {
+	httpClient, err := options.newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
 	buildOptions := []elastic.ClientOptionFunc{
 		elastic.SetURL(options.Host),
 		elastic.SetSniff(false),
 		elastic.SetHealthcheck(false),
+		elastic.SetHttpClient(httpClient),
 	}
 
 	if options.User != "" && options.Password != "" {
@@ -116,6 +125,76 @@ func (c *V6Client) GetAliasedIndex(aliasName string) (string, error) {
 	return "", fmt.Errorf("alias '%s' doesn't target any index", aliasName)
 }
 
+func (c *V6Client) ListAliases(pattern string) (map[string]string, error) {
+	aliasResult, err := c.client.
+		Aliases().
+		Alias(pattern).
+		Do(context.Background())
+
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return map[string]string{}, nil
+		}
+
+		return nil, err
+	}
+
+	aliases := map[string]string{}
+
+	for indexName, index := range aliasResult.Indices {
+		for _, alias := range index.Aliases {
+			if err := addAliasTarget(aliases, alias.AliasName, indexName); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return aliases, nil
+}
+
+// GetIndexAliases returns the names of the aliases targeting the index.
+func (c *V6Client) GetIndexAliases(indexName string) ([]string, error) {
+	aliasResult, err := c.client.
+		Aliases().
+		Index(indexName).
+		Do(context.Background())
+
+	if err != nil {
+		return nil, err
+	}
+
+	aliasNames := []string{}
+
+	for _, alias := range aliasResult.Indices[indexName].Aliases {
+		aliasNames = append(aliasNames, alias.AliasName)
+	}
+
+	return aliasNames, nil
+}
+
+func (c *V6Client) ListIndices(pattern string) ([]IndexInfo, error) {
+	rows, err := c.client.
+		CatIndices().
+		Index(pattern).
+		Columns(catIndicesColumns()...).
+		Do(context.Background())
+
+	if err != nil {
+		return nil, err
+	}
+
+	indices := []IndexInfo{}
+
+	for _, row := range rows {
+		indices = append(
+			indices,
+			newIndexInfo(row.Index, row.Health, row.Status, int64(row.DocsCount), row.StoreSize, row.CreationDate),
+		)
+	}
+
+	return indices, nil
+}
+
 func (c *V6Client) GetIndexConfiguration(indexName string) (configuration.Index, error) {
 	indexResult, err := c.client.
 		IndexGet(indexName).
@@ -132,23 +211,71 @@ func (c *V6Client) GetIndexConfiguration(indexName string) (configuration.Index,
 	), nil
 }
 
-func (c *V6Client) Reindex(sourceIndexName string, targetIndexName string) error {
-	return c.reindex(sourceIndexName, targetIndexName, true)
+// StartReindex isn't retried, a lost response would start a second task filling the same index.
+func (c *V6Client) StartReindex(sourceIndexName string, targetIndexName string) (string, error) {
+	result, err := c.client.
+		Reindex().
+		SourceIndex(sourceIndexName).
+		DestinationIndexAndType(targetIndexName, "_doc").
+		WaitForCompletion(false).
+		Refresh("true").
+		DoAsync(context.Background())
+
+	if err != nil {
+		return "", err
+	}
+
+	return result.TaskId, nil
 }
 
-func (c *V6Client) reindex(sourceIndexName string, targetIndexName string, shouldRetry bool) error {
-	_, err := c.client.
+func (c *V6Client) StartCatchUpReindex(
+	sourceIndexName string,
+	targetIndexName string,
+	timestampField string,
+	since time.Time,
+) (string, error) {
+	result, err := c.client.
 		Reindex().
-		SourceIndex(sourceIndexName).
+		Source(
+			elastic.NewReindexSource().
+				Index(sourceIndexName).
+				Query(
+					elastic.NewRangeQuery(timestampField).
+						Gte(since.UnixNano()/int64(time.Millisecond)).
+						Format("epoch_millis"),
+				),
+		).
 		DestinationIndexAndType(targetIndexName, "_doc").
-		WaitForCompletion(true).
+		WaitForCompletion(false).
 		Refresh("true").
-		Do(context.Background())
+		DoAsync(context.Background())
 
-	if err != nil && shouldRetry == true {
-		return c.reindex(sourceIndexName, targetIndexName, false)
+	if err != nil {
+		return "", err
 	}
 
+	return result.TaskId, nil
+}
+
+func (c *V6Client) GetReindexTask(taskID string) (ReindexTask, error) {
+	response, err := c.client.PerformRequest(context.Background(), elastic.PerformRequestOptions{
+		Method: "GET",
+		Path:   "/_tasks/" + url.PathEscape(taskID),
+	})
+
+	if err != nil {
+		return ReindexTask{}, err
+	}
+
+	return parseReindexTask(taskID, response.Body)
+}
+
+func (c *V6Client) CancelReindexTask(taskID string) error {
+	_, err := c.client.PerformRequest(context.Background(), elastic.PerformRequestOptions{
+		Method: "POST",
+		Path:   "/_tasks/" + url.PathEscape(taskID) + "/_cancel",
+	})
+
 	return err
 }
 
@@ -164,3 +291,226 @@ func (c *V6Client) UpdateIndexConfiguration(indexName string, configuration conf
 
 	return nil
 }
+
+func (c *V6Client) UpdateIndexSettings(indexName string, settings configuration.Settings) error {
+	_, err := c.client.
+		IndexPutSettings(indexName).
+		BodyJson(settings).
+		Do(context.Background())
+
+	return err
+}
+
+func (c *V6Client) DeleteIndex(indexName string) error {
+	_, err := c.client.DeleteIndex(indexName).Do(context.Background())
+
+	return err
+}
+
+func (c *V6Client) CloseIndex(indexName string) error {
+	_, err := c.client.CloseIndex(indexName).Do(context.Background())
+
+	return err
+}
+
+func (c *V6Client) OpenIndex(indexName string) error {
+	_, err := c.client.OpenIndex(indexName).Do(context.Background())
+
+	return err
+}
+
+func (c *V6Client) RefreshIndex(indexName string) error {
+	_, err := c.client.Refresh(indexName).Do(context.Background())
+
+	return err
+}
+
+func (c *V6Client) CountDocuments(indexName string) (int64, error) {
+	return c.client.Count(indexName).Do(context.Background())
+}
+
+func (c *V6Client) SampleDocuments(indexName string, size int) (Documents, error) {
+	searchResult, err := c.client.
+		Search(indexName).
+		Query(
+			elastic.NewFunctionScoreQuery().
+				Query(elastic.NewMatchAllQuery()).
+				AddScoreFunc(elastic.NewRandomFunction()),
+		).
+		Size(size).
+		Do(context.Background())
+
+	if err != nil {
+		return nil, err
+	}
+
+	documents := Documents{}
+
+	for _, hit := range searchResult.Hits.Hits {
+		if hit.Source != nil {
+			documents[hit.Id] = *hit.Source
+		}
+	}
+
+	return documents, nil
+}
+
+func (c *V6Client) GetDocuments(indexName string, ids []string) (Documents, error) {
+	documents := Documents{}
+
+	if len(ids) == 0 {
+		return documents, nil
+	}
+
+	mget := c.client.Mget()
+	for _, id := range ids {
+		mget = mget.Add(elastic.NewMultiGetItem().Index(indexName).Id(id))
+	}
+
+	mgetResult, err := mget.Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range mgetResult.Docs {
+		if doc.Found && doc.Source != nil {
+			documents[doc.Id] = *doc.Source
+		}
+	}
+
+	return documents, nil
+}
+
+// SearchDocuments returns the _source of the hits, it returns no document when the index doesn't exist.
+func (c *V6Client) SearchDocuments(indexName string, search map[string]interface{}) ([]json.RawMessage, error) {
+	searchResult, err := c.client.
+		Search(indexName).
+		Source(search).
+		Do(context.Background())
+
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return []json.RawMessage{}, nil
+		}
+
+		return nil, err
+	}
+
+	sources := []json.RawMessage{}
+
+	for _, hit := range searchResult.Hits.Hits {
+		if hit.Source != nil {
+			sources = append(sources, *hit.Source)
+		}
+	}
+
+	return sources, nil
+}
+
+func (c *V6Client) SetWriteBlock(indexName string, blocked bool) error {
+	_, err := c.client.
+		IndexPutSettings(indexName).
+		BodyJson(writeBlockSettings(blocked)).
+		Do(context.Background())
+
+	return err
+}
+
+// GetDocument returns nil when either the document or the index doesn't exist.
+func (c *V6Client) GetDocument(indexName string, id string) (*Document, error) {
+	getResult, err := c.client.
+		Get().
+		Index(indexName).
+		Type("_doc").
+		Id(id).
+		Do(context.Background())
+
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if !getResult.Found || getResult.Source == nil || getResult.SeqNo == nil || getResult.PrimaryTerm == nil {
+		return nil, nil
+	}
+
+	return &Document{
+		Source: *getResult.Source,
+		Version: DocumentVersion{
+			SeqNo:       *getResult.SeqNo,
+			PrimaryTerm: *getResult.PrimaryTerm,
+		},
+	}, nil
+}
+
+func (c *V6Client) CreateDocument(indexName string, id string, source interface{}) (DocumentVersion, error) {
+	indexResult, err := c.client.
+		Index().
+		Index(indexName).
+		Type("_doc").
+		Id(id).
+		OpType("create").
+		BodyJson(source).
+		Do(context.Background())
+
+	if err != nil {
+		if elastic.IsConflict(err) {
+			return DocumentVersion{}, &DocumentConflictError{IndexName: indexName, ID: id}
+		}
+
+		return DocumentVersion{}, err
+	}
+
+	return DocumentVersion{SeqNo: indexResult.SeqNo, PrimaryTerm: indexResult.PrimaryTerm}, nil
+}
+
+func (c *V6Client) UpdateDocument(
+	indexName string,
+	id string,
+	source interface{},
+	version DocumentVersion,
+) (DocumentVersion, error) {
+	indexResult, err := c.client.
+		Index().
+		Index(indexName).
+		Type("_doc").
+		Id(id).
+		IfSeqNo(version.SeqNo).
+		IfPrimaryTerm(version.PrimaryTerm).
+		BodyJson(source).
+		Do(context.Background())
+
+	if err != nil {
+		if elastic.IsConflict(err) || elastic.IsNotFound(err) {
+			return DocumentVersion{}, &DocumentConflictError{IndexName: indexName, ID: id}
+		}
+
+		return DocumentVersion{}, err
+	}
+
+	return DocumentVersion{SeqNo: indexResult.SeqNo, PrimaryTerm: indexResult.PrimaryTerm}, nil
+}
+
+// DeleteDocument doesn't fail when the document has already been deleted.
+func (c *V6Client) DeleteDocument(indexName string, id string, version DocumentVersion) error {
+	_, err := c.client.
+		Delete().
+		Index(indexName).
+		Type("_doc").
+		Id(id).
+		IfSeqNo(version.SeqNo).
+		IfPrimaryTerm(version.PrimaryTerm).
+		Do(context.Background())
+
+	switch {
+	case elastic.IsNotFound(err):
+		return nil
+	case elastic.IsConflict(err):
+		return &DocumentConflictError{IndexName: indexName, ID: id}
+	}
+
+	return err
+}
@@ -0,0 +1,23 @@
+package elasticsearch
+
+// V8Client is an Elasticsearch client for elasticsearch v8. Create one by calling NewV8Client.
+//
+// Elasticsearch 8 is secure by default: the credentials are never sent over an unencrypted connection, and the
+// certificates are verified unless InsecureSkipVerify is set.
+type V8Client struct {
+	*restClient
+}
+
+// NewV8Client creates a V8Client instance.
+func NewV8Client(
+	options Options,
+) (*V8Client, error) {
+	client, err := newRestClient(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &V8Client{
+		restClient: client,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package linediff
+
+type Operation int
+
+const (
+	Equal Operation = iota
+	Delete
+	Insert
+)
+
+type Diff struct {
+	Operation Operation
+	Line      string
+}
+
+// Lines returns the shortest list of operations turning the from lines into the to lines.
+// It follows the greedy algorithm of E. Myers, "An O(ND) Difference Algorithm and Its Variations":
+// the time is O((n+m)D) and the memory O(D²), D being the number of deleted and inserted lines,
+// which stays small for configuration files even when they are long.
+func Lines(from []string, to []string) []Diff {
+	n, m := len(from), len(to)
+	maxEdits := n + m
+	offset := maxEdits + 1
+
+	// furthest[offset+k] is the furthest x reached on the diagonal k = x - y.
+	furthest := make([]int, 2*maxEdits+3)
+	// trace[d] keeps the furthest x of the diagonals -d..d once d edits have been made.
+	trace := [][]int{}
+
+	for edits := 0; edits <= maxEdits; edits++ {
+		for k := -edits; k <= edits; k += 2 {
+			x := 0
+			if k == -edits || (k != edits && furthest[offset+k-1] < furthest[offset+k+1]) {
+				x = furthest[offset+k+1]
+			} else {
+				x = furthest[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && from[x] == to[y] {
+				x++
+				y++
+			}
+
+			furthest[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(from, to, trace)
+			}
+		}
+
+		trace = append(trace, append([]int(nil), furthest[offset-edits:offset+edits+1]...))
+	}
+
+	return backtrack(from, to, trace)
+}
+
+// backtrack walks the edits back from the end of both lists,
+// the trace holds the diagonals reached before the last edit.
+func backtrack(from []string, to []string, trace [][]int) []Diff {
+	x, y := len(from), len(to)
+	reversed := make([]Diff, 0, len(from)+len(to))
+
+	for edits := len(trace); edits > 0; edits-- {
+		previous := trace[edits-1]
+		furthest := func(k int) int { return previous[k+edits-1] }
+
+		k := x - y
+		previousK := k - 1
+
+		if k == -edits || (k != edits && furthest(k-1) < furthest(k+1)) {
+			previousK = k + 1
+		}
+
+		previousX := furthest(previousK)
+		previousY := previousX - previousK
+
+		for x > previousX && y > previousY {
+			x--
+			y--
+			reversed = append(reversed, Diff{Operation: Equal, Line: from[x]})
+		}
+
+		if x == previousX {
+			y--
+			reversed = append(reversed, Diff{Operation: Insert, Line: to[y]})
+		} else {
+			x--
+			reversed = append(reversed, Diff{Operation: Delete, Line: from[x]})
+		}
+	}
+
+	for x > 0 {
+		x--
+		reversed = append(reversed, Diff{Operation: Equal, Line: from[x]})
+	}
+
+	diffs := make([]Diff, 0, len(reversed))
+	for i := len(reversed) - 1; i >= 0; i-- {
+		diffs = append(diffs, reversed[i])
+	}
+
+	return diffs
+}
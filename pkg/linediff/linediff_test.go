@@ -0,0 +1,125 @@
+package linediff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchy/stretchy/pkg/linediff"
+)
+
+func TestLines(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from     []string
+		to       []string
+		expected []linediff.Diff
+	}{
+		{
+			name:     "empty",
+			from:     []string{},
+			to:       []string{},
+			expected: []linediff.Diff{},
+		},
+		{
+			name: "equal",
+			from: []string{"a", "b"},
+			to:   []string{"a", "b"},
+			expected: []linediff.Diff{
+				{Operation: linediff.Equal, Line: "a"},
+				{Operation: linediff.Equal, Line: "b"},
+			},
+		},
+		{
+			name: "created",
+			from: []string{},
+			to:   []string{"a"},
+			expected: []linediff.Diff{
+				{Operation: linediff.Insert, Line: "a"},
+			},
+		},
+		{
+			name: "deleted",
+			from: []string{"a"},
+			to:   nil,
+			expected: []linediff.Diff{
+				{Operation: linediff.Delete, Line: "a"},
+			},
+		},
+		{
+			name: "changed",
+			from: []string{"a", "b", "c", "d"},
+			to:   []string{"a", "x", "c", "d", "e"},
+			expected: []linediff.Diff{
+				{Operation: linediff.Equal, Line: "a"},
+				{Operation: linediff.Delete, Line: "b"},
+				{Operation: linediff.Insert, Line: "x"},
+				{Operation: linediff.Equal, Line: "c"},
+				{Operation: linediff.Equal, Line: "d"},
+				{Operation: linediff.Insert, Line: "e"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, linediff.Lines(tc.from, tc.to))
+		})
+	}
+}
+
+func TestLines_Shortest(t *testing.T) {
+	testCases := []struct {
+		name          string
+		from          []string
+		to            []string
+		expectedEdits int
+	}{
+		{
+			name:          "moved line",
+			from:          []string{"a", "b", "c", "d", "e"},
+			to:            []string{"b", "c", "d", "e", "a"},
+			expectedEdits: 2,
+		},
+		{
+			name:          "repeated lines",
+			from:          []string{"}", "}", "a", "}", "}"},
+			to:            []string{"}", "a", "}", "b", "}", "}"},
+			expectedEdits: 3,
+		},
+		{
+			name:          "nothing in common",
+			from:          []string{"a", "b"},
+			to:            []string{"c", "d", "e"},
+			expectedEdits: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			from, to := []string{}, []string{}
+			edits := 0
+
+			for _, diff := range linediff.Lines(tc.from, tc.to) {
+				if diff.Operation != linediff.Insert {
+					from = append(from, diff.Line)
+				}
+
+				if diff.Operation != linediff.Delete {
+					to = append(to, diff.Line)
+				}
+
+				if diff.Operation != linediff.Equal {
+					edits++
+				}
+			}
+
+			assert.Equal(t, tc.from, from)
+			assert.Equal(t, tc.to, to)
+			assert.Equal(t, tc.expectedEdits, edits)
+		})
+	}
+}
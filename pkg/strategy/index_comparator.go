@@ -1,6 +1,9 @@
 package strategy
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/stretchy/stretchy/pkg/configuration"
 )
 
@@ -11,10 +14,42 @@ const (
 	IndexDecisionCreate
 	IndexDecisionMigrate
 	IndexDecisionUpdate
+	IndexDecisionUpdateAnalysis
+	// IndexDecisionRollback is never decided by the voter, it records in the history an alias moved back.
+	IndexDecisionRollback
 )
 
 func (id IndexAction) String() string {
-	return []string{"None", "Create", "Migrate", "Update"}[id]
+	return indexActionNames()[id]
+}
+
+func indexActionNames() []string {
+	return []string{"None", "Create", "Migrate", "Update", "UpdateAnalysis", "Rollback"}
+}
+
+func NewIndexActionFromString(action string) (IndexAction, error) {
+	for i, name := range indexActionNames() {
+		if name == action {
+			return IndexAction(i), nil
+		}
+	}
+
+	return IndexDecisionNone, fmt.Errorf("unknown index action '%s'", action)
+}
+
+func (id IndexAction) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+func (id *IndexAction) UnmarshalText(text []byte) error {
+	action, err := NewIndexActionFromString(string(text))
+	if err != nil {
+		return err
+	}
+
+	*id = action
+
+	return nil
 }
 
 type IndexActionVoter struct {
@@ -50,6 +85,30 @@ func (indexIndexComparatorResult IndexVoterResult) Changes() configuration.Chang
 	return indexIndexComparatorResult.changes
 }
 
+type jsonIndexVoterResult struct {
+	Action  IndexAction                    `json:"action"`
+	Changes configuration.ChangeCollection `json:"changes"`
+}
+
+func (indexIndexComparatorResult IndexVoterResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonIndexVoterResult{
+		Action:  indexIndexComparatorResult.action,
+		Changes: indexIndexComparatorResult.changes,
+	})
+}
+
+func (indexIndexComparatorResult *IndexVoterResult) UnmarshalJSON(data []byte) error {
+	jsonResult := jsonIndexVoterResult{}
+	if err := json.Unmarshal(data, &jsonResult); err != nil {
+		return err
+	}
+
+	indexIndexComparatorResult.action = jsonResult.Action
+	indexIndexComparatorResult.changes = jsonResult.Changes
+
+	return nil
+}
+
 func (ic *IndexActionVoter) Compare(
 	currentConfiguration *configuration.Index,
 	newConfiguration *configuration.Index,
@@ -81,6 +140,13 @@ func (ic *IndexActionVoter) Compare(
 		), nil
 	}
 
+	if ic.allowSoftUpdate && ic.canBeAnAnalysisUpdate(currentConfiguration, changes) {
+		return NewIndexVoterResult(
+			IndexDecisionUpdateAnalysis,
+			changes,
+		), nil
+	}
+
 	return NewIndexVoterResult(
 		IndexDecisionMigrate,
 		changes,
@@ -88,9 +154,10 @@ func (ic *IndexActionVoter) Compare(
 }
 
 // A soft update should be possible only when there are only properties addition
+// and changes of dynamic settings, the static ones require a new index.
 func (ic IndexActionVoter) canBeASoftUpdate(changes configuration.ChangeCollection) bool {
 	for _, c := range changes {
-		if c.Path[0] == "settings" {
+		if c.Path[0] == "settings" && !c.IsDynamicSetting() {
 			return false
 		}
 
@@ -109,3 +176,33 @@ func (ic IndexActionVoter) canBeASoftUpdate(changes configuration.ChangeCollecti
 
 	return true
 }
+
+// An analysis update should be possible only when the changed analysis components are not used by the
+// current mappings, since the existing documents would not be analyzed again.
+// The other changes must be allowed by a soft update.
+func (ic IndexActionVoter) canBeAnAnalysisUpdate(
+	currentConfiguration *configuration.Index,
+	changes configuration.ChangeCollection,
+) bool {
+	usedComponents := currentConfiguration.UsedAnalysisComponents()
+	otherChanges := configuration.ChangeCollection{}
+
+	for _, c := range changes {
+		if !c.IsAnalysisChange() {
+			otherChanges = append(otherChanges, c)
+			continue
+		}
+
+		if c.Type == configuration.ChangeTypeDelete {
+			return false
+		}
+
+		for _, component := range c.AnalysisComponents() {
+			if usedComponents[component] {
+				return false
+			}
+		}
+	}
+
+	return len(otherChanges) < len(changes) && ic.canBeASoftUpdate(otherChanges)
+}
@@ -1,6 +1,7 @@
 package strategy_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -105,13 +106,7 @@ func TestIndexActionVoter_Compare_Update(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	testCases := []struct {
-		name                     string
-		allowSoftUpdate          bool
-		newIndexConfiguration    *configuration.Index
-		expectedChangeCollection configuration.ChangeCollection
-		expectedDecision         strategy.IndexAction
-	}{
+	testCases := []updateTestCase{
 		{
 			name:                  "NewField SoftUpdate enabled",
 			allowSoftUpdate:       true,
@@ -142,6 +137,65 @@ func TestIndexActionVoter_Compare_Update(t *testing.T) {
 		},
 	}
 
+	runUpdateTestCases(t, testCases)
+}
+
+func TestIndexActionVoter_Compare_Update_DynamicSetting(t *testing.T) {
+	settingsWithChangedReplicas := getIndexExample()
+
+	err := settingsWithChangedReplicas.GetSettings().Merge(
+		map[string]interface{}{
+			"index": map[string]interface{}{
+				"number_of_replicas": 3,
+			},
+		},
+	)
+	assert.NoError(t, err)
+
+	testCases := []updateTestCase{
+		{
+			name:                  "Dynamic setting SoftUpdate enabled",
+			allowSoftUpdate:       true,
+			newIndexConfiguration: settingsWithChangedReplicas,
+			expectedChangeCollection: configuration.ChangeCollection{
+				configuration.Change{
+					Type: configuration.ChangeTypeUpdate,
+					Path: []string{"settings", "index", "number_of_replicas"},
+					From: 1,
+					To:   3,
+				},
+			},
+			expectedDecision: strategy.IndexDecisionUpdate,
+		},
+		{
+			name:                  "Dynamic setting SoftUpdate disabled",
+			allowSoftUpdate:       false,
+			newIndexConfiguration: settingsWithChangedReplicas,
+			expectedChangeCollection: configuration.ChangeCollection{
+				configuration.Change{
+					Type: configuration.ChangeTypeUpdate,
+					Path: []string{"settings", "index", "number_of_replicas"},
+					From: 1,
+					To:   3,
+				},
+			},
+			expectedDecision: strategy.IndexDecisionMigrate,
+		},
+	}
+
+	runUpdateTestCases(t, testCases)
+}
+
+type updateTestCase struct {
+	name                     string
+	allowSoftUpdate          bool
+	newIndexConfiguration    *configuration.Index
+	expectedChangeCollection configuration.ChangeCollection
+	expectedDecision         strategy.IndexAction
+}
+
+// runUpdateTestCases compares each new configuration with the index example.
+func runUpdateTestCases(t *testing.T, testCases []updateTestCase) {
 	for _, tc := range testCases {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
@@ -262,6 +316,10 @@ func TestIndexDecision_String(t *testing.T) {
 			actionName:    "Update",
 			indexDecision: strategy.IndexDecisionUpdate,
 		},
+		{
+			actionName:    "UpdateAnalysis",
+			indexDecision: strategy.IndexDecisionUpdateAnalysis,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -290,3 +348,171 @@ func TestIndexActionVoter_Compare_ErrorOnWrongPropertyChange(t *testing.T) {
 	_, err = indexActionVoter.Compare(getIndexExample(), index)
 	assert.Error(t, err)
 }
+
+func TestNewIndexActionFromString(t *testing.T) {
+	for _, action := range []strategy.IndexAction{
+		strategy.IndexDecisionNone,
+		strategy.IndexDecisionCreate,
+		strategy.IndexDecisionMigrate,
+		strategy.IndexDecisionUpdate,
+	} {
+		action := action
+		t.Run(action.String(), func(t *testing.T) {
+			parsedAction, err := strategy.NewIndexActionFromString(action.String())
+			assert.NoError(t, err)
+			assert.Equal(t, action, parsedAction)
+		})
+	}
+
+	_, err := strategy.NewIndexActionFromString("Unknown")
+	assert.Error(t, err)
+}
+
+func TestIndexVoterResult_JSON(t *testing.T) {
+	result := strategy.NewIndexVoterResult(
+		strategy.IndexDecisionUpdate,
+		configuration.ChangeCollection{
+			configuration.Change{
+				Type: configuration.ChangeTypeCreate,
+				Path: []string{"mappings", "properties", "field2"},
+				From: nil,
+				To:   "keyword",
+			},
+		},
+	)
+
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t,
+		`{"action":"Update","changes":[`+
+			`{"type":"CREATE","path":["mappings","properties","field2"],"from":null,"to":"keyword"}`+
+			`]}`,
+		string(data),
+	)
+
+	decodedResult := strategy.IndexVoterResult{}
+	assert.NoError(t, json.Unmarshal(data, &decodedResult))
+	assert.Equal(t, result, decodedResult)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"action":"Unknown"}`), &decodedResult))
+}
+
+func getIndexWithAnalysisExample() *configuration.Index {
+	i := configuration.New(
+		map[string]interface{}{
+			"properties": map[string]interface{}{
+				"title": map[string]interface{}{
+					"type":     "text",
+					"analyzer": "title_analyzer",
+				},
+			},
+		},
+		map[string]interface{}{
+			"analysis": map[string]interface{}{
+				"analyzer": map[string]interface{}{
+					"title_analyzer": map[string]interface{}{
+						"tokenizer": "standard",
+						"filter":    []interface{}{"lowercase"},
+					},
+				},
+			},
+		},
+	)
+
+	return &i
+}
+
+// newAnalyzerSettings adds an analyzer which no field uses.
+func newAnalyzerSettings() map[string]interface{} {
+	return map[string]interface{}{
+		"index": map[string]interface{}{
+			"analysis": map[string]interface{}{
+				"analyzer": map[string]interface{}{
+					"title_analyzer": map[string]interface{}{
+						"tokenizer": "standard",
+						"filter":    []interface{}{"lowercase"},
+					},
+					"folding_analyzer": map[string]interface{}{
+						"tokenizer": "standard",
+						"filter":    []interface{}{"asciifolding"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// changedAnalyzerSettings changes the analyzer used by a field.
+func changedAnalyzerSettings() map[string]interface{} {
+	return map[string]interface{}{
+		"index": map[string]interface{}{
+			"analysis": map[string]interface{}{
+				"analyzer": map[string]interface{}{
+					"title_analyzer": map[string]interface{}{
+						"tokenizer": "whitespace",
+						"filter":    []interface{}{"lowercase"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getIndexWithAnalysisSettings(t *testing.T, settings map[string]interface{}) *configuration.Index {
+	index := getIndexWithAnalysisExample()
+	assert.NoError(t, index.GetSettings().Merge(settings))
+
+	return index
+}
+
+func TestIndexActionVoter_Compare_UpdateAnalysis(t *testing.T) {
+	withANewAnalyzer := getIndexWithAnalysisSettings(t, newAnalyzerSettings())
+	withAChangedAnalyzer := getIndexWithAnalysisSettings(t, changedAnalyzerSettings())
+
+	newAnalyzerAndStaticSettings := newAnalyzerSettings()
+	newAnalyzerAndStaticSettings["index"].(map[string]interface{})["number_of_shards"] = 5
+	withANewAnalyzerAndAStaticSetting := getIndexWithAnalysisSettings(t, newAnalyzerAndStaticSettings)
+
+	testCases := []struct {
+		name                  string
+		allowSoftUpdate       bool
+		newIndexConfiguration *configuration.Index
+		expectedDecision      strategy.IndexAction
+	}{
+		{
+			name:                  "New analyzer SoftUpdate enabled",
+			allowSoftUpdate:       true,
+			newIndexConfiguration: withANewAnalyzer,
+			expectedDecision:      strategy.IndexDecisionUpdateAnalysis,
+		},
+		{
+			name:                  "New analyzer SoftUpdate disabled",
+			allowSoftUpdate:       false,
+			newIndexConfiguration: withANewAnalyzer,
+			expectedDecision:      strategy.IndexDecisionMigrate,
+		},
+		{
+			name:                  "Analyzer used by a field",
+			allowSoftUpdate:       true,
+			newIndexConfiguration: withAChangedAnalyzer,
+			expectedDecision:      strategy.IndexDecisionMigrate,
+		},
+		{
+			name:                  "New analyzer and static setting",
+			allowSoftUpdate:       true,
+			newIndexConfiguration: withANewAnalyzerAndAStaticSetting,
+			expectedDecision:      strategy.IndexDecisionMigrate,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			indexActionVoter := strategy.NewIndexActionVoter(tc.allowSoftUpdate)
+			result, err := indexActionVoter.Compare(getIndexWithAnalysisExample(), tc.newIndexConfiguration)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedDecision, result.Action())
+		})
+	}
+}